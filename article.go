@@ -0,0 +1,305 @@
+package textproc
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// Article is the main content of a news page, result of HTMLExtractArticle
+type Article struct {
+	Title  string
+	Byline string
+	// PublishedTime is the raw publish time in the page, formats are
+	// different between sites so it is not parsed,
+	// example: "2020-08-06T07:10:00+07:00", "Thứ năm, 6/8/2020, 07:10 (GMT+7)"
+	PublishedTime string
+	// LeadImage is an absolute URL, it can be empty
+	LeadImage string
+	// Text is the clean body text, paragraphs are separated by a new line
+	Text string
+	// Node is the element that contains the body, it can be nil
+	Node *html.Node
+}
+
+var (
+	articlePositiveRegexp = regexp.MustCompile(
+		`(?i)article|body|content|entry|main|post|text|story|detail|blog`)
+	articleNegativeRegexp = regexp.MustCompile(
+		`(?i)comment|foot|menu|nav|sidebar|side|related|social|share|` +
+			`tag|breadcrumb|widget|banner|ads?[-_ ]|advert|promo|sponsor|popup|` +
+			`meta|author|date|login|subscribe|hidden|combx|masthead`)
+	articleBylineRegexp = regexp.MustCompile(`(?i)byline|author|writtenby`)
+	articleDateRegexp   = regexp.MustCompile(`(?i)date|time|publish`)
+	// tags that are never part of the article body
+	articleExcludedTags = map[string]bool{
+		"script": true, "style": true, "noscript": true, "nav": true,
+		"aside": true, "footer": true, "form": true, "button": true,
+		"iframe": true, "svg": true, "select": true, "input": true,
+	}
+	// tags that contain paragraph text
+	articleParagraphTags = map[string]bool{
+		"p": true, "pre": true, "td": true, "blockquote": true, "li": true,
+		"dd": true, "h2": true, "h3": true,
+	}
+)
+
+// HTMLExtractArticle finds the main content of a news page (removes menus,
+// footers, sidebars, ...) by scoring blocks with text density, link density
+// and tag semantics (in the style of Readability).
+// Title, published time and lead image are taken from HTMLGetMetadata if
+// the page does not have them in the content. The page URL is the canonical
+// URL (link rel=canonical, og:url, ...), relative URLs are resolved in the
+// same way as HTMLGetHREFs(canonicalURL, node), the lead image is empty if it
+// cannot be resolved to an absolute URL.
+func HTMLExtractArticle(node *html.Node) Article {
+	var ret Article
+	meta := htmlGetMetadata(node)
+	// the canonical URL itself can be relative to <base href>
+	if meta.URL != "" {
+		if canonical, err := htmlResolveURL(htmlDocumentBase(nil, node), meta.URL); err == nil {
+			meta.URL = canonical
+		}
+	}
+	pageURL, _ := url.Parse(meta.URL)
+	meta.resolveURLs(htmlDocumentBase(pageURL, node))
+
+	ret.Title = articleTitle(node, meta)
+	ret.Byline = articleByline(node, meta)
+	ret.PublishedTime = articlePublishedTime(node, meta)
+
+	ret.Node = articleTopCandidate(node)
+	if ret.Node != nil {
+		ret.Text = htmlGetText(ret.Node, isArticleJunk)
+	}
+
+	if strings.HasPrefix(meta.Image, "http") {
+		ret.LeadImage = meta.Image
+	}
+	if ret.LeadImage == "" && ret.Node != nil {
+		imgs, _ := HTMLXPath(ret.Node, `.//img`)
+		for _, img := range imgs {
+			if src := HTMLGetImgSrc(meta.URL, img); src != "" {
+				ret.LeadImage = src
+				break
+			}
+		}
+	}
+	return ret
+}
+
+// articleTitle returns the first h1, or the metadata title
+func articleTitle(node *html.Node, meta PageMeta) string {
+	if h1s, _ := HTMLXPath(node, `//h1`); len(h1s) > 0 {
+		if title := NormalizeText(firstLine(HTMLGetText(h1s[0]))); title != "" {
+			return title
+		}
+	}
+	return meta.Title
+}
+
+func articleByline(node *html.Node, meta PageMeta) string {
+	var found *html.Node
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		if found != nil || n.Type == html.ElementNode && articleExcludedTags[n.Data] {
+			return
+		}
+		if n.Type == html.ElementNode {
+			if htmlGetAttr(n, "rel") == "author" ||
+				strings.Contains(htmlGetAttr(n, "itemprop"), "author") ||
+				articleBylineRegexp.MatchString(htmlGetAttr(n, "class")+" "+htmlGetAttr(n, "id")) {
+				found = n
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+	f(node)
+	if found != nil {
+		text := htmlGetText(found, func(n *html.Node) bool {
+			return n != found && n.Type == html.ElementNode &&
+				articleDateRegexp.MatchString(htmlGetAttr(n, "class"))
+		})
+		if byline := firstLine(text); byline != "" {
+			return byline
+		}
+	}
+	return meta.Author
+}
+
+func articlePublishedTime(node *html.Node, meta PageMeta) string {
+	if meta.PublishedTime != "" {
+		return meta.PublishedTime
+	}
+	if times, _ := HTMLXPath(node, `//time[@datetime]`); len(times) > 0 {
+		return htmlGetAttr(times[0], "datetime")
+	}
+	elems, _ := HTMLXPath(node, `//*[@class]`)
+	for _, elem := range elems {
+		if articleExcludedTags[elem.Data] ||
+			!articleDateRegexp.MatchString(htmlGetAttr(elem, "class")) {
+			continue
+		}
+		if text := firstLine(HTMLGetText(elem)); text != "" &&
+			strings.IndexAny(text, "0123456789") != -1 {
+			return text
+		}
+	}
+	return ""
+}
+
+func firstLine(text string) string {
+	if i := strings.Index(text, "\n"); i != -1 {
+		text = text[:i]
+	}
+	return strings.TrimSpace(text)
+}
+
+// articleClassWeight returns a positive weight if the class or id of the
+// element looks like the article body, a negative weight if it looks like
+// boilerplate
+func articleClassWeight(n *html.Node) float64 {
+	weight := 0.0
+	for _, v := range []string{htmlGetAttr(n, "class"), htmlGetAttr(n, "id")} {
+		if v == "" {
+			continue
+		}
+		if articleNegativeRegexp.MatchString(v) {
+			weight -= 25
+		}
+		if articlePositiveRegexp.MatchString(v) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+// articleTagWeight returns initial score of a candidate by its tag
+func articleTagWeight(n *html.Node) float64 {
+	switch n.Data {
+	case "article":
+		return 10
+	case "div", "section", "main":
+		return 5
+	case "pre", "td", "blockquote":
+		return 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		return -3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		return -5
+	}
+	return 0
+}
+
+// isArticleJunk returns true for subtrees that should be removed from
+// the article text
+func isArticleJunk(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	if articleExcludedTags[n.Data] {
+		return true
+	}
+	if articleClassWeight(n) < 0 {
+		textLen, linkLen := htmlTextLength(n)
+		return textLen < 200 || float64(linkLen) > 0.3*float64(textLen)
+	}
+	return false
+}
+
+// htmlTextLength returns number of chars in text nodes of the subtree, and
+// number of chars in text nodes that are inside links
+func htmlTextLength(node *html.Node) (textLen int, linkLen int) {
+	var f func(*html.Node, bool)
+	f = func(n *html.Node, inLink bool) {
+		if n.Type == html.ElementNode && articleExcludedTags[n.Data] {
+			return
+		}
+		if n.Type == html.TextNode {
+			l := utf8.RuneCountInString(strings.TrimSpace(n.Data))
+			textLen += l
+			if inLink {
+				linkLen += l
+			}
+		}
+		isLink := inLink || n.Type == html.ElementNode && n.Data == "a"
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c, isLink)
+		}
+	}
+	f(node, false)
+	return textLen, linkLen
+}
+
+// articleTopCandidate returns the element that most likely contains the
+// article body
+func articleTopCandidate(node *html.Node) *html.Node {
+	scores := make(map[*html.Node]float64)
+	order := make([]*html.Node, 0) // document order of scored nodes
+	addScore := func(n *html.Node, score float64) {
+		if n == nil || n.Type != html.ElementNode {
+			return
+		}
+		if _, found := scores[n]; !found {
+			scores[n] = articleTagWeight(n) + articleClassWeight(n)
+			order = append(order, n)
+		}
+		scores[n] += score
+	}
+
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		if n.Type == html.ElementNode && (articleExcludedTags[n.Data] ||
+			articleClassWeight(n) < 0 && articlePositiveRegexp.FindString(
+				htmlGetAttr(n, "class")+htmlGetAttr(n, "id")) == "") {
+			return
+		}
+		if n.Type == html.ElementNode && articleParagraphTags[n.Data] {
+			text := HTMLGetText(n)
+			if nChars := utf8.RuneCountInString(text); nChars >= 25 {
+				// 1 point for the paragraph, 1 point for each comma,
+				// 1 point for each 100 chars (max 3)
+				score := 1 + float64(strings.Count(text, ",")) +
+					float64(min(nChars/100, 3))
+				addScore(n.Parent, score)
+				if n.Parent != nil {
+					addScore(n.Parent.Parent, score/2)
+				}
+			}
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+	f(node)
+	if len(scores) == 0 {
+		return nil
+	}
+
+	type candidate struct {
+		node  *html.Node
+		score float64
+	}
+	candidates := make([]candidate, 0, len(scores))
+	for _, n := range order {
+		score := scores[n]
+		textLen, linkLen := htmlTextLength(n)
+		linkDensity := 0.0
+		if textLen > 0 {
+			linkDensity = float64(linkLen) / float64(textLen)
+		}
+		candidates = append(candidates, candidate{node: n, score: score * (1 - linkDensity)})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	return candidates[0].node
+}
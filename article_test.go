@@ -0,0 +1,96 @@
+package textproc
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestHTMLExtractArticle(t *testing.T) {
+	file, err := os.ReadFile("html_test_file2.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	article := HTMLExtractArticle(HTMLParseToNode(file))
+	if article.Title != "Tồn kho tại Mỹ giảm, giá dầu lên đỉnh 5 tháng, vàng tiếp tục lập đỉnh lịch sử" {
+		t.Errorf("error article Title: %v", article.Title)
+	}
+	if article.Byline != "Như Tâm (Theo Reuters)" {
+		t.Errorf("error article Byline: %v", article.Byline)
+	}
+	if article.PublishedTime != "Thứ năm, 6/8/2020, 07:10 (GMT+7)" {
+		t.Errorf("error article PublishedTime: %v", article.PublishedTime)
+	}
+	if article.LeadImage != "https://i.ndh.vn/2020/08/06/nygold94-5195-1596672583.gif" {
+		t.Errorf("error article LeadImage: %v", article.LeadImage)
+	}
+	if !strings.HasPrefix(article.Text, "Giá dầu Brent tương lai tăng 74 cent") ||
+		!strings.HasSuffix(article.Text, "Giá platinum tăng 2,7% lên 962,63 USD/ounce.") {
+		t.Errorf("error article Text: %v", article.Text)
+	}
+}
+
+func TestHTMLExtractArticleMeta(t *testing.T) {
+	page := `<html><head>
+<title>Site name | Some title</title>
+<meta property="og:title" content="Og title">
+<meta property="og:url" content="https://example.com/news/1.html">
+<meta property="og:image" content="/img/lead.jpg">
+<meta property="article:published_time" content="2020-08-06T07:10:00+07:00">
+<meta name="author" content="Meta Author">
+</head><body>
+<nav><ul><li><a href="/">Home</a></li><li><a href="/news">News, sport, weather and everything</a></li></ul></nav>
+<div class="sidebar"><p>Báo cáo các gợi ý không phù hợp, hãy liên hệ với chúng tôi ngay.</p></div>
+<div id="story">
+<p>First paragraph of the story, it is long enough to be scored by the extractor.</p>
+<p>Second paragraph, with some commas, some more commas, and even more text here.</p>
+<div class="share"><a href="/fb">Share on Facebook</a> <a href="/tw">Tweet</a></div>
+</div>
+<footer><p>Copyright 2025, all rights reserved, do not copy anything from here.</p></footer>
+</body></html>`
+	article := HTMLExtractArticle(HTMLParseToNode(page))
+	if article.Title != "Og title" || article.Byline != "Meta Author" ||
+		article.PublishedTime != "2020-08-06T07:10:00+07:00" ||
+		article.LeadImage != "https://example.com/img/lead.jpg" {
+		t.Errorf("error HTMLExtractArticle: %+v", article)
+	}
+	if article.Text != `First paragraph of the story, it is long enough to be scored by the extractor.
+Second paragraph, with some commas, some more commas, and even more text here.` {
+		t.Errorf("error article Text: %v", article.Text)
+	}
+}
+
+func TestHTMLExtractArticleBase(t *testing.T) {
+	page := `<html><head><base href="https://cdn.example.com/static/">
+<link rel="canonical" href="https://example.com/news/1.html">
+<meta property="og:image" content="img/lead.jpg">
+</head><body><h1>Hoà bình e` + "́" + `</h1>
+<div id="story"><p>First paragraph of the story, it is long enough to be scored by the extractor.</p></div>
+</body></html>`
+	article := HTMLExtractArticle(HTMLParseToNode(page))
+	if e := "https://cdn.example.com/static/img/lead.jpg"; article.LeadImage != e {
+		t.Errorf("error HTMLExtractArticle LeadImage: real: %v, expected: %v", article.LeadImage, e)
+	}
+	if e := NormalizeText("Hoà bình é"); article.Title != e {
+		t.Errorf("error HTMLExtractArticle Title: real: %q, expected: %q", article.Title, e)
+	}
+}
+
+func TestHTMLExtractArticleOrganizationJSONLD(t *testing.T) {
+	page := `<html><head><title>Tin nóng - NDH</title>
+<meta property="og:title" content="Tin nóng">
+<meta property="og:url" content="https://ndh.vn/news/1.html">
+<meta property="og:image" content="img/lead.jpg">
+<script type="application/ld+json">{"@context": "https://schema.org",
+"@type": "Organization", "name": "NDH", "url": "https://ndh.vn"}</script>
+</head><body><div id="story">
+<p>First paragraph of the story, it is long enough to be scored by the extractor.</p>
+</div></body></html>`
+	article := HTMLExtractArticle(HTMLParseToNode(page))
+	if article.Title != "Tin nóng" {
+		t.Errorf("error HTMLExtractArticle Title: real: %v, expected: %v", article.Title, "Tin nóng")
+	}
+	if e := "https://ndh.vn/news/img/lead.jpg"; article.LeadImage != e {
+		t.Errorf("error HTMLExtractArticle LeadImage: real: %v, expected: %v", article.LeadImage, e)
+	}
+}
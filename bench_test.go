@@ -42,6 +42,39 @@ func BenchmarkTextToNGrams(b *testing.B) {
 	}
 }
 
+func BenchmarkTextToHashedNGrams(b *testing.B) {
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		for _, para := range paragraphs {
+			TextToHashedNGrams(para, 2)
+		}
+	}
+}
+
+// BenchmarkHashTextToNGrams is the string-building way to get the results
+// of TextToHashedNGrams
+func BenchmarkHashTextToNGrams(b *testing.B) {
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		for _, para := range paragraphs {
+			result := make(map[uint64]int)
+			for nGram, count := range TextToNGrams(para, 2) {
+				result[uint64(HashTextToInt(nGram))] += count
+			}
+		}
+	}
+}
+
+func BenchmarkForEachNGram(b *testing.B) {
+	b.ReportAllocs()
+	sum := uint64(0)
+	for n := 0; n < b.N; n++ {
+		for _, para := range paragraphs {
+			ForEachNGram(para, 2, func(hash uint64) { sum += hash })
+		}
+	}
+}
+
 func BenchmarkGenRandomWord(b *testing.B) {
 	for n := 0; n < b.N; n++ {
 		GenRandomWord(8, 12, AlphaNumericList)
@@ -73,3 +106,29 @@ Giới thiệu
 		RemoveRedundantSpace(redundantText)
 	}
 }
+
+var benchHTML = HTMLParseToNode(`<html><body><div class="content">
+<p>Đoạn một <a href="/tin/1.html">tin 1</a></p>
+<p>Đoạn hai <a href="/tin/2.html">tin 2</a></p>
+<ul><li><a href="https://example.com/a">a</a></li><li><a href="/b#c">b</a></li></ul>
+</div></body></html>`)
+
+func BenchmarkHTMLXPath(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		HTMLXPath(benchHTML, "//div[@class='content']//a/@href")
+	}
+}
+
+func BenchmarkCompiledXPath(b *testing.B) {
+	compiled := MustCompileXPath("//div[@class='content']//a/@href")
+	for n := 0; n < b.N; n++ {
+		compiled.All(benchHTML)
+	}
+}
+
+func BenchmarkCompileUncached(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		compiled, _ := Compile("//div[@class='content']//a/@href")
+		compiled.All(benchHTML)
+	}
+}
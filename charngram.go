@@ -0,0 +1,74 @@
+package textproc
+
+import (
+	"strings"
+)
+
+// CharNGramOptions configures TextToCharNGrams
+type CharNGramOptions struct {
+	// PadWords makes n-grams of each word separately, the word is padded
+	// with a space at both ends, so n-grams at the start and the end of
+	// words are distinct, example n=3: "abc" => " ab", "abc", "bc ".
+	// A padded word that is shorter than n is one n-gram.
+	// If PadWords is false, n-grams cross words (words are joined by a space).
+	PadWords bool
+	// RemoveDiacritics applies RemoveVietnamDiacritic before making n-grams,
+	// so "Hà Nội" and "Ha Noi" have the same n-grams
+	RemoveDiacritics bool
+}
+
+// TextToCharNGrams creates a set of character n-grams (shingles) from
+// the lowercase words of the text (punctuations removed), n is number of
+// chars (runes) in a n-gram
+func TextToCharNGrams(text string, n int, opts CharNGramOptions) map[string]int {
+	result := make(map[string]int)
+	forEachCharNGram(text, n, opts, func(nGram string) { result[nGram] += 1 })
+	return result
+}
+
+// TextToHashedCharNGrams is TextToCharNGrams but keys are hashes of
+// n-grams (uint64(HashTextToInt(nGram))), it does not allocate a string for
+// each n-gram
+func TextToHashedCharNGrams(text string, n int, opts CharNGramOptions) map[uint64]int {
+	result := make(map[uint64]int)
+	forEachCharNGram(text, n, opts, func(nGram string) { result[fnv64a(nGram)] += 1 })
+	return result
+}
+
+// forEachCharNGram calls f for each n-gram, the n-gram is a substring of a
+// normalized text so it must not be modified
+func forEachCharNGram(text string, n int, opts CharNGramOptions, f func(nGram string)) {
+	if n <= 0 {
+		return
+	}
+	text = strings.ToLower(text)
+	if opts.RemoveDiacritics {
+		text = RemoveVietnamDiacritic(text)
+	}
+	words := TextToWords(text)
+	offsets := make([]int, 0, 64) // rune offsets of the current segment, reused
+	emit := func(segment string) {
+		offsets = offsets[:0]
+		for i := range segment {
+			offsets = append(offsets, i)
+		}
+		offsets = append(offsets, len(segment))
+		nRunes := len(offsets) - 1
+		if nRunes < n {
+			if opts.PadWords && nRunes > 0 {
+				f(segment)
+			}
+			return
+		}
+		for i := 0; i+n <= nRunes; i++ {
+			f(segment[offsets[i]:offsets[i+n]])
+		}
+	}
+	if !opts.PadWords {
+		emit(strings.Join(words, " "))
+		return
+	}
+	for _, word := range words {
+		emit(" " + word + " ")
+	}
+}
@@ -0,0 +1,47 @@
+package textproc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTextToCharNGrams(t *testing.T) {
+	r := TextToCharNGrams("Hà Nội!", 3, CharNGramOptions{})
+	e := map[string]int{"hà ": 1, "à n": 1, " nộ": 1, "nội": 1}
+	if !reflect.DeepEqual(r, e) {
+		t.Errorf("error TextToCharNGrams: real: %v, expected: %v", r, e)
+	}
+
+	r = TextToCharNGrams("Hà Nội, ha noi", 3,
+		CharNGramOptions{PadWords: true, RemoveDiacritics: true})
+	e = map[string]int{" ha": 2, "ha ": 2, " no": 2, "noi": 2, "oi ": 2}
+	if !reflect.DeepEqual(r, e) {
+		t.Errorf("error TextToCharNGrams padded: real: %v, expected: %v", r, e)
+	}
+
+	r = TextToCharNGrams("a bc", 4, CharNGramOptions{PadWords: true})
+	e = map[string]int{" a ": 1, " bc ": 1}
+	if !reflect.DeepEqual(r, e) {
+		t.Errorf("error TextToCharNGrams short word: real: %v, expected: %v", r, e)
+	}
+	if r := TextToCharNGrams("ab", 3, CharNGramOptions{}); len(r) != 0 {
+		t.Errorf("error TextToCharNGrams short text: %v", r)
+	}
+}
+
+func TestTextToHashedCharNGrams(t *testing.T) {
+	for _, opts := range []CharNGramOptions{{}, {PadWords: true}, {RemoveDiacritics: true}} {
+		text := "Thị trường chứng khoán, thị trường"
+		nGrams := TextToCharNGrams(text, 4, opts)
+		hashed := TextToHashedCharNGrams(text, 4, opts)
+		if len(hashed) != len(nGrams) {
+			t.Errorf("error TextToHashedCharNGrams len: real: %v, expected: %v", len(hashed), len(nGrams))
+		}
+		for nGram, count := range nGrams {
+			if hashed[uint64(HashTextToInt(nGram))] != count {
+				t.Errorf("error TextToHashedCharNGrams %q: real: %v, expected: %v",
+					nGram, hashed[uint64(HashTextToInt(nGram))], count)
+			}
+		}
+	}
+}
@@ -0,0 +1,864 @@
+package textproc
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// HTMLQuerySelector returns the first element in the subtree (in document
+// order) that matches the CSS selector, returns nil if no element matches
+func HTMLQuerySelector(node *html.Node, css string) (*html.Node, error) {
+	selector, err := parseCSSSelector(css)
+	if err != nil {
+		return nil, err
+	}
+	var found *html.Node
+	htmlWalkElements(node, func(n *html.Node) bool {
+		if selector.match(n) {
+			found = n
+			return false
+		}
+		return true
+	})
+	return found, nil
+}
+
+// HTMLQuerySelectorAll returns all elements in the subtree (the node itself
+// is excluded) that match the CSS selector, in document order.
+// Supported: type, universal, #id, .class, attribute selectors ([a], [a=v],
+// [a~=v], [a|=v], [a^=v], [a$=v], [a*=v], with i flag), combinators
+// (descendant, >, +, ~), selector lists, :not(), :is(), :where(), :has(),
+// :nth-child(), :nth-last-child(), :nth-of-type(), :nth-last-of-type(),
+// :first-child, :last-child, :only-child, :first-of-type, :last-of-type,
+// :only-of-type, :empty, :root.
+func HTMLQuerySelectorAll(node *html.Node, css string) ([]*html.Node, error) {
+	selector, err := parseCSSSelector(css)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*html.Node, 0)
+	htmlWalkElements(node, func(n *html.Node) bool {
+		if selector.match(n) {
+			ret = append(ret, n)
+		}
+		return true
+	})
+	return ret, nil
+}
+
+// CheckValidCSSSelector returns nil if the input CSS selector is valid
+func CheckValidCSSSelector(css string) error {
+	_, err := parseCSSSelector(css)
+	return err
+}
+
+// htmlWalkElements calls f for all descendant elements of the node in
+// document order, stops if f returns false
+func htmlWalkElements(node *html.Node, f func(*html.Node) bool) {
+	var walk func(*html.Node) bool
+	walk = func(n *html.Node) bool {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && !f(c) {
+				return false
+			}
+			if !walk(c) {
+				return false
+			}
+		}
+		return true
+	}
+	walk(node)
+}
+
+// cssSelectorList is a comma separated list of complex selectors
+type cssSelectorList []cssComplex
+
+// cssComplex is compound selectors joined by combinators,
+// combinators[i] is between compounds[i] and compounds[i+1]
+type cssComplex struct {
+	// leading is the combinator before the first compound of a relative
+	// selector (argument of :has), example: '>' in ":has(> img)"
+	leading     byte
+	compounds   []cssCompound
+	combinators []byte // ' ', '>', '+', '~'
+}
+
+type cssCompound struct {
+	tag        string // lowercase, empty means any element
+	conditions []cssCondition
+}
+
+type cssConditionKind int
+
+const (
+	cssID cssConditionKind = iota
+	cssClass
+	cssAttr
+	cssPseudo // simple pseudo class, example: first-child
+	cssNth    // nth-child, nth-last-child, nth-of-type, nth-last-of-type
+	cssNot
+	cssIs // is, where
+	cssHas
+)
+
+type cssCondition struct {
+	kind       cssConditionKind
+	name       string // attribute name or pseudo class name
+	op         string // attribute operator, empty means the attribute exists
+	value      string
+	ignoreCase bool
+	a, b       int // nth: an+b
+	selectors  cssSelectorList
+}
+
+func (l cssSelectorList) match(n *html.Node) bool {
+	for _, complex := range l {
+		if complex.match(n, len(complex.compounds)-1, nil) {
+			return true
+		}
+	}
+	return false
+}
+
+// match checks compounds[0:i+1] from right to left, n is matched against
+// compounds[i]. If scope is not nil (relative selector), the element that
+// matches compounds[0] must be related to the scope by the leading combinator.
+func (cx cssComplex) match(n *html.Node, i int, scope *html.Node) bool {
+	if !cx.compounds[i].match(n) {
+		return false
+	}
+	if i == 0 {
+		return scope == nil || cssRelated(scope, n, cx.leading)
+	}
+	switch cx.combinators[i-1] {
+	case ' ':
+		for p := htmlParentElement(n); p != nil; p = htmlParentElement(p) {
+			if cx.match(p, i-1, scope) {
+				return true
+			}
+		}
+	case '>':
+		p := htmlParentElement(n)
+		return p != nil && cx.match(p, i-1, scope)
+	case '+':
+		p := htmlPrevElement(n)
+		return p != nil && cx.match(p, i-1, scope)
+	case '~':
+		for p := htmlPrevElement(n); p != nil; p = htmlPrevElement(p) {
+			if cx.match(p, i-1, scope) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cssRelated returns true if the element n is related to the scope by the
+// combinator, example: '>' means n is a child of scope
+func cssRelated(scope *html.Node, n *html.Node, combinator byte) bool {
+	switch combinator {
+	case '>':
+		return n.Parent == scope
+	case '+':
+		return htmlPrevElement(n) == scope
+	case '~':
+		for p := htmlPrevElement(n); p != nil; p = htmlPrevElement(p) {
+			if p == scope {
+				return true
+			}
+		}
+		return false
+	}
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (c cssCompound) match(n *html.Node) bool {
+	if n.Type != html.ElementNode || c.tag != "" && n.Data != c.tag {
+		return false
+	}
+	for _, cond := range c.conditions {
+		if !cond.match(n) {
+			return false
+		}
+	}
+	return true
+}
+
+func (cond cssCondition) match(n *html.Node) bool {
+	switch cond.kind {
+	case cssID:
+		return htmlGetAttr(n, "id") == cond.value
+	case cssClass:
+		return hasToken(htmlGetAttr(n, "class"), cond.value, true)
+	case cssAttr:
+		return cond.matchAttr(n)
+	case cssNth:
+		return cssNthMatch(cond.a, cond.b, cssSiblingIndex(n, cond.name))
+	case cssNot:
+		return !cond.selectors.match(n)
+	case cssIs:
+		return cond.selectors.match(n)
+	case cssHas:
+		found := false
+		check := func(e *html.Node) bool {
+			for _, complex := range cond.selectors {
+				if complex.match(e, len(complex.compounds)-1, n) {
+					found = true
+					return false
+				}
+			}
+			return true
+		}
+		htmlWalkElements(n, check)
+		// "+" and "~" relative selectors match following siblings
+		for s := htmlNextElement(n); s != nil && !found && cond.hasSiblingSelector(); s = htmlNextElement(s) {
+			if check(s) {
+				htmlWalkElements(s, check)
+			}
+		}
+		return found
+	}
+	// cssPseudo
+	switch cond.name {
+	case "first-child":
+		return htmlPrevElement(n) == nil
+	case "last-child":
+		return htmlNextElement(n) == nil
+	case "only-child":
+		return htmlPrevElement(n) == nil && htmlNextElement(n) == nil
+	case "first-of-type":
+		return cssSiblingIndex(n, "nth-of-type") == 1
+	case "last-of-type":
+		return cssSiblingIndex(n, "nth-last-of-type") == 1
+	case "only-of-type":
+		return cssSiblingIndex(n, "nth-of-type") == 1 &&
+			cssSiblingIndex(n, "nth-last-of-type") == 1
+	case "empty":
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode || c.Type == html.TextNode {
+				return false
+			}
+		}
+		return true
+	case "root":
+		return n.Parent != nil && n.Parent.Type == html.DocumentNode
+	}
+	return false
+}
+
+func (cond cssCondition) hasSiblingSelector() bool {
+	for _, complex := range cond.selectors {
+		if complex.leading == '+' || complex.leading == '~' {
+			return true
+		}
+	}
+	return false
+}
+
+func (cond cssCondition) matchAttr(n *html.Node) bool {
+	if !htmlHasAttr(n, cond.name) {
+		return false
+	}
+	if cond.op == "" {
+		return true
+	}
+	v, expected := htmlGetAttr(n, cond.name), cond.value
+	if cond.ignoreCase {
+		v, expected = strings.ToLower(v), strings.ToLower(expected)
+	}
+	switch cond.op {
+	case "=":
+		return v == expected
+	case "~=":
+		return hasToken(v, expected, true)
+	case "|=":
+		return v == expected || strings.HasPrefix(v, expected+"-")
+	case "^=":
+		return expected != "" && strings.HasPrefix(v, expected)
+	case "$=":
+		return expected != "" && strings.HasSuffix(v, expected)
+	case "*=":
+		return expected != "" && strings.Contains(v, expected)
+	}
+	return false
+}
+
+// cssSiblingIndex returns 1-based index of the element among its element
+// siblings, nth is one of: nth-child, nth-last-child, nth-of-type,
+// nth-last-of-type
+func cssSiblingIndex(n *html.Node, nth string) int {
+	ofType := strings.HasSuffix(nth, "of-type")
+	next := htmlPrevElement
+	if strings.HasPrefix(nth, "nth-last") {
+		next = htmlNextElement
+	}
+	index := 1
+	for s := next(n); s != nil; s = next(s) {
+		if !ofType || s.Data == n.Data {
+			index++
+		}
+	}
+	return index
+}
+
+// cssNthMatch returns true if index = a*k + b for an integer k >= 0
+func cssNthMatch(a int, b int, index int) bool {
+	if a == 0 {
+		return index == b
+	}
+	diff := index - b
+	return diff%a == 0 && diff/a >= 0
+}
+
+func htmlParentElement(n *html.Node) *html.Node {
+	if n.Parent != nil && n.Parent.Type == html.ElementNode {
+		return n.Parent
+	}
+	return nil
+}
+
+func htmlPrevElement(n *html.Node) *html.Node {
+	for s := n.PrevSibling; s != nil; s = s.PrevSibling {
+		if s.Type == html.ElementNode {
+			return s
+		}
+	}
+	return nil
+}
+
+func htmlNextElement(n *html.Node) *html.Node {
+	for s := n.NextSibling; s != nil; s = s.NextSibling {
+		if s.Type == html.ElementNode {
+			return s
+		}
+	}
+	return nil
+}
+
+// cssParser is a recursive descent parser of CSS selectors
+type cssParser struct {
+	s   string
+	pos int
+}
+
+var cssSimplePseudos = toMapStrings("first-child", "last-child", "only-child",
+	"first-of-type", "last-of-type", "only-of-type", "empty", "root")
+
+func parseCSSSelector(css string) (cssSelectorList, error) {
+	p := &cssParser{s: css}
+	list, err := p.parseList(false)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.s) {
+		return nil, p.errorf("unexpected %q", p.s[p.pos])
+	}
+	return list, nil
+}
+
+func (p *cssParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("error css selector %q at %v: %v", p.s, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *cssParser) peek() byte {
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+// skipSpaces returns true if there was at least one space
+func (p *cssParser) skipSpaces() bool {
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte(" \t\n\r\f", p.s[p.pos]) != -1 {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+// parseList parses comma separated selectors until the end or ")"
+func (p *cssParser) parseList(relative bool) (cssSelectorList, error) {
+	ret := make(cssSelectorList, 0)
+	for {
+		complex, err := p.parseComplex(relative)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, complex)
+		if p.peek() != ',' {
+			return ret, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *cssParser) parseComplex(relative bool) (cssComplex, error) {
+	ret := cssComplex{leading: ' '}
+	p.skipSpaces()
+	if c := p.peek(); relative && (c == '>' || c == '+' || c == '~') {
+		ret.leading = c
+		p.pos++
+		p.skipSpaces()
+	}
+	for {
+		compound, err := p.parseCompound()
+		if err != nil {
+			return ret, err
+		}
+		ret.compounds = append(ret.compounds, compound)
+		hasSpace := p.skipSpaces()
+		c := p.peek()
+		switch {
+		case c == 0 || c == ',' || c == ')':
+			return ret, nil
+		case c == '>' || c == '+' || c == '~':
+			ret.combinators = append(ret.combinators, c)
+			p.pos++
+			p.skipSpaces()
+		case hasSpace:
+			ret.combinators = append(ret.combinators, ' ')
+		default:
+			return ret, p.errorf("unexpected %q", c)
+		}
+	}
+}
+
+func (p *cssParser) parseCompound() (cssCompound, error) {
+	var ret cssCompound
+	start := p.pos
+	if p.peek() == '*' {
+		p.pos++
+	} else if p.isIdentChar() {
+		ret.tag = strings.ToLower(p.parseIdent())
+	}
+	for {
+		var cond cssCondition
+		var err error
+		switch p.peek() {
+		case '#':
+			p.pos++
+			cond = cssCondition{kind: cssID, value: p.parseIdent()}
+			if cond.value == "" {
+				return ret, p.errorf("expected id")
+			}
+		case '.':
+			p.pos++
+			cond = cssCondition{kind: cssClass, value: p.parseIdent()}
+			if cond.value == "" {
+				return ret, p.errorf("expected class name")
+			}
+		case '[':
+			cond, err = p.parseAttr()
+		case ':':
+			cond, err = p.parsePseudo()
+		default:
+			if p.pos == start {
+				return ret, p.errorf("expected selector")
+			}
+			return ret, nil
+		}
+		if err != nil {
+			return ret, err
+		}
+		ret.conditions = append(ret.conditions, cond)
+	}
+}
+
+func (p *cssParser) parseAttr() (cssCondition, error) {
+	p.pos++ // [
+	p.skipSpaces()
+	ret := cssCondition{kind: cssAttr, name: strings.ToLower(p.parseIdent())}
+	if ret.name == "" {
+		return ret, p.errorf("expected attribute name")
+	}
+	p.skipSpaces()
+	if p.peek() != ']' {
+		for _, op := range []string{"=", "~=", "|=", "^=", "$=", "*="} {
+			if strings.HasPrefix(p.s[p.pos:], op) {
+				ret.op = op
+				p.pos += len(op)
+				break
+			}
+		}
+		if ret.op == "" {
+			return ret, p.errorf("expected attribute operator")
+		}
+		p.skipSpaces()
+		if c := p.peek(); c == '"' || c == '\'' {
+			value, err := p.parseString()
+			if err != nil {
+				return ret, err
+			}
+			ret.value = value
+		} else if ret.value = p.parseIdent(); ret.value == "" {
+			return ret, p.errorf("expected attribute value")
+		}
+		p.skipSpaces()
+		if c := p.peek(); c == 'i' || c == 'I' || c == 's' || c == 'S' {
+			ret.ignoreCase = c == 'i' || c == 'I'
+			p.pos++
+			p.skipSpaces()
+		}
+	}
+	if p.peek() != ']' {
+		return ret, p.errorf("expected ]")
+	}
+	p.pos++
+	return ret, nil
+}
+
+func (p *cssParser) parsePseudo() (cssCondition, error) {
+	p.pos++ // :
+	if p.peek() == ':' {
+		return cssCondition{}, p.errorf("pseudo elements are not supported")
+	}
+	name := strings.ToLower(p.parseIdent())
+	if p.peek() != '(' {
+		if !cssSimplePseudos[name] {
+			return cssCondition{}, p.errorf("unsupported pseudo class %q", name)
+		}
+		return cssCondition{kind: cssPseudo, name: name}, nil
+	}
+	p.pos++ // (
+	var ret cssCondition
+	switch name {
+	case "not", "is", "where", "has":
+		ret = cssCondition{kind: cssIs, name: name}
+		if name == "not" {
+			ret.kind = cssNot
+		} else if name == "has" {
+			ret.kind = cssHas
+		}
+		selectors, err := p.parseList(name == "has")
+		if err != nil {
+			return ret, err
+		}
+		ret.selectors = selectors
+	case "nth-child", "nth-last-child", "nth-of-type", "nth-last-of-type":
+		end := strings.IndexByte(p.s[p.pos:], ')')
+		if end == -1 {
+			return ret, p.errorf("expected )")
+		}
+		a, b, err := parseCSSNth(p.s[p.pos : p.pos+end])
+		if err != nil {
+			return ret, p.errorf("%v", err)
+		}
+		ret = cssCondition{kind: cssNth, name: name, a: a, b: b}
+		p.pos += end
+	default:
+		return ret, p.errorf("unsupported pseudo class %q", name)
+	}
+	p.skipSpaces()
+	if p.peek() != ')' {
+		return ret, p.errorf("expected )")
+	}
+	p.pos++
+	return ret, nil
+}
+
+var cssNthRegexp = regexp.MustCompile(`^([+-]?\d*)n([+-]\d+)?$`)
+
+// parseCSSNth parses the argument of :nth-child, example: "2n+1", "odd", "3"
+func parseCSSNth(arg string) (a int, b int, err error) {
+	arg = strings.ToLower(strings.Join(strings.Fields(arg), ""))
+	switch arg {
+	case "odd":
+		return 2, 1, nil
+	case "even":
+		return 2, 0, nil
+	}
+	if b, err := strconv.Atoi(arg); err == nil {
+		return 0, b, nil
+	}
+	m := cssNthRegexp.FindStringSubmatch(arg)
+	if m == nil {
+		return 0, 0, fmt.Errorf("invalid nth argument %q", arg)
+	}
+	switch m[1] {
+	case "", "+":
+		a = 1
+	case "-":
+		a = -1
+	default:
+		a, _ = strconv.Atoi(m[1])
+	}
+	if m[2] != "" {
+		b, _ = strconv.Atoi(m[2])
+	}
+	return a, b, nil
+}
+
+func (p *cssParser) isIdentChar() bool {
+	c := p.peek()
+	return c == '-' || c == '_' || c == '\\' || c >= 0x80 ||
+		'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// parseIdent parses an identifier, backslash escapes are decoded,
+// returns empty string if there is no identifier at the position
+func (p *cssParser) parseIdent() string {
+	var buf strings.Builder
+	for p.pos < len(p.s) && p.isIdentChar() {
+		if p.s[p.pos] == '\\' {
+			p.pos++
+			buf.WriteString(p.parseEscape())
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+		buf.WriteRune(r)
+		p.pos += size
+	}
+	return buf.String()
+}
+
+// parseEscape decodes an escape after a backslash: up to 6 hex digits
+// (followed by an optional space) or any other char
+func (p *cssParser) parseEscape() string {
+	end := p.pos
+	for end < len(p.s) && end-p.pos < 6 && strings.IndexByte("0123456789abcdefABCDEF", p.s[end]) != -1 {
+		end++
+	}
+	if end > p.pos {
+		code, _ := strconv.ParseInt(p.s[p.pos:end], 16, 32)
+		p.pos = end
+		if p.peek() == ' ' {
+			p.pos++
+		}
+		return string(rune(code))
+	}
+	if p.pos >= len(p.s) {
+		return ""
+	}
+	r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+	p.pos += size
+	return string(r)
+}
+
+func (p *cssParser) parseString() (string, error) {
+	quote := p.s[p.pos]
+	p.pos++
+	var buf strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		switch c {
+		case quote:
+			p.pos++
+			return buf.String(), nil
+		case '\\':
+			p.pos++
+			buf.WriteString(p.parseEscape())
+		default:
+			buf.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// CSSToXPath translates a CSS selector to an equivalent XPath that can be
+// used with HTMLXPath (the result selects descendants of the context node).
+// A selector list is translated to an union, so results may not be in
+// document order. Pseudo classes *-of-type on the universal selector and
+// complex selectors in :not(), :is() are not supported.
+func CSSToXPath(css string) (string, error) {
+	selector, err := parseCSSSelector(css)
+	if err != nil {
+		return "", err
+	}
+	paths := make([]string, 0, len(selector))
+	for _, complex := range selector {
+		path, err := complex.toXPath(".")
+		if err != nil {
+			return "", fmt.Errorf("error CSSToXPath %q: %v", css, err)
+		}
+		paths = append(paths, path)
+	}
+	return strings.Join(paths, " | "), nil
+}
+
+// toXPath returns the location path of the complex selector from the context
+func (cx cssComplex) toXPath(context string) (string, error) {
+	var buf strings.Builder
+	buf.WriteString(context)
+	for i, compound := range cx.compounds {
+		combinator := cx.leading
+		if i > 0 {
+			combinator = cx.combinators[i-1]
+		}
+		step, err := compound.toXPath()
+		if err != nil {
+			return "", err
+		}
+		switch combinator {
+		case '>':
+			buf.WriteString("/" + step)
+		case '+':
+			buf.WriteString("/following-sibling::*[1]/self::" + step)
+		case '~':
+			buf.WriteString("/following-sibling::" + step)
+		default:
+			buf.WriteString("//" + step)
+		}
+	}
+	return buf.String(), nil
+}
+
+// toXPath returns a location step, example: "p[@id='a']"
+func (c cssCompound) toXPath() (string, error) {
+	tag := c.tag
+	if tag == "" {
+		tag = "*"
+	}
+	var buf strings.Builder
+	buf.WriteString(tag)
+	for _, cond := range c.conditions {
+		predicate, err := cond.toXPath(tag)
+		if err != nil {
+			return "", err
+		}
+		buf.WriteString("[" + predicate + "]")
+	}
+	return buf.String(), nil
+}
+
+// toXPath returns a predicate expression of the condition
+func (cond cssCondition) toXPath(tag string) (string, error) {
+	switch cond.kind {
+	case cssID:
+		return "@id=" + xpathLiteral(cond.value), nil
+	case cssClass:
+		return xpathHasToken("@class", cond.value), nil
+	case cssAttr:
+		return cond.attrToXPath(), nil
+	case cssNth, cssPseudo:
+		return cond.pseudoToXPath(tag)
+	case cssNot, cssIs:
+		alternatives := make([]string, 0, len(cond.selectors))
+		for _, complex := range cond.selectors {
+			if len(complex.compounds) != 1 {
+				return "", fmt.Errorf("complex selector in :%v", cond.name)
+			}
+			step, err := complex.compounds[0].toXPath()
+			if err != nil {
+				return "", err
+			}
+			alternatives = append(alternatives, "self::"+step)
+		}
+		expr := strings.Join(alternatives, " or ")
+		if cond.kind == cssNot {
+			return "not(" + expr + ")", nil
+		}
+		return expr, nil
+	case cssHas:
+		alternatives := make([]string, 0, len(cond.selectors))
+		for _, complex := range cond.selectors {
+			path, err := complex.toXPath(".")
+			if err != nil {
+				return "", err
+			}
+			alternatives = append(alternatives, path)
+		}
+		return strings.Join(alternatives, " or "), nil
+	}
+	return "", fmt.Errorf("unsupported condition")
+}
+
+func (cond cssCondition) attrToXPath() string {
+	attr, value := "@"+cond.name, cond.value
+	if cond.ignoreCase {
+		attr = "translate(" + attr + ", 'ABCDEFGHIJKLMNOPQRSTUVWXYZ', 'abcdefghijklmnopqrstuvwxyz')"
+		value = strings.ToLower(value)
+	}
+	v := xpathLiteral(value)
+	switch cond.op {
+	case "=":
+		return attr + "=" + v
+	case "~=":
+		return xpathHasToken(attr, value)
+	case "|=":
+		return attr + "=" + v + " or starts-with(" + attr + ", " + xpathLiteral(value+"-") + ")"
+	case "^=", "$=", "*=":
+		if value == "" {
+			return "false()"
+		}
+		switch cond.op {
+		case "^=":
+			return "starts-with(" + attr + ", " + v + ")"
+		case "$=":
+			return fmt.Sprintf("substring(%v, string-length(%v) - %v)=%v",
+				attr, attr, len([]rune(value))-1, v)
+		}
+		return "contains(" + attr + ", " + v + ")"
+	}
+	return "@" + cond.name
+}
+
+func (cond cssCondition) pseudoToXPath(tag string) (string, error) {
+	name := cond.name
+	a, b := cond.a, cond.b
+	switch name {
+	case "first-child", "first-of-type":
+		name, a, b = strings.Replace(name, "first", "nth", 1), 0, 1
+	case "last-child", "last-of-type":
+		name, a, b = strings.Replace(name, "last", "nth-last", 1), 0, 1
+	case "only-child":
+		return "not(preceding-sibling::*) and not(following-sibling::*)", nil
+	case "only-of-type":
+		if tag == "*" {
+			return "", fmt.Errorf(":only-of-type needs a type selector")
+		}
+		return "not(preceding-sibling::" + tag + ") and not(following-sibling::" + tag + ")", nil
+	case "empty":
+		return "not(*) and not(text())", nil
+	case "root":
+		return "not(parent::*)", nil
+	}
+	axis := "preceding-sibling::"
+	if strings.HasPrefix(name, "nth-last") {
+		axis = "following-sibling::"
+	}
+	siblingTest := "*"
+	if strings.HasSuffix(name, "of-type") {
+		if tag == "*" {
+			return "", fmt.Errorf(":%v needs a type selector", cond.name)
+		}
+		siblingTest = tag
+	}
+	index := "(count(" + axis + siblingTest + ") + 1)"
+	switch {
+	case a == 0:
+		return fmt.Sprintf("%v = %v", index, b), nil
+	case a > 0:
+		return fmt.Sprintf("%v >= %v and (%v - %v) mod %v = 0", index, b, index, b, a), nil
+	}
+	return fmt.Sprintf("%v <= %v and (%v - %v) mod %v = 0", index, b, b, index, -a), nil
+}
+
+// xpathHasToken returns an expression that checks if the space separated
+// list expression contains the token
+func xpathHasToken(list string, token string) string {
+	return "contains(concat(' ', normalize-space(" + list + "), ' '), " +
+		xpathLiteral(" "+token+" ") + ")"
+}
+
+// xpathLiteral quotes a string as an XPath string literal
+func xpathLiteral(s string) string {
+	if !strings.Contains(s, "'") {
+		return "'" + s + "'"
+	}
+	if !strings.Contains(s, `"`) {
+		return `"` + s + `"`
+	}
+	parts := strings.Split(s, "'")
+	return "concat('" + strings.Join(parts, `', "'", '`) + "')"
+}
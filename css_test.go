@@ -0,0 +1,125 @@
+package textproc
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+const cssTestHTML = `<html><body>
+<div id="main" class="content article">
+  <h1 class="title">Tiêu đề</h1>
+  <p class="lead">p1</p>
+  <p>p2 <a href="https://a.vn/1" rel="nofollow">a1</a></p>
+  <p lang="vi-VN">p3 <img src="x.jpg"></p>
+  <ul><li>l1</li><li class="x">l2</li><li>l3</li><li>l4</li><li>l5</li></ul>
+  <span></span>
+</div>
+<div class="sidebar"><a href="/2" data-type="Tag">a2</a><p>p4</p></div>
+</body></html>`
+
+// cssTestIDs returns texts of the first text node of the nodes
+func cssTestIDs(nodes []*html.Node) string {
+	ids := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		id := n.Data
+		if n.FirstChild != nil && n.FirstChild.Type == html.TextNode &&
+			strings.TrimSpace(n.FirstChild.Data) != "" {
+			id = strings.TrimSpace(n.FirstChild.Data)
+		}
+		ids = append(ids, id)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestHTMLQuerySelectorAll(t *testing.T) {
+	root := HTMLParseToNode(cssTestHTML)
+	for _, c := range []struct{ css, expected string }{
+		{"p", "p1,p2,p3,p4"},
+		{"#main > p.lead", "p1"},
+		{"div.content.article p", "p1,p2,p3"},
+		{".sidebar a, h1", "Tiêu đề,a2"},
+		{"h1 + p", "p1"},
+		{"h1 ~ p", "p1,p2,p3"},
+		{"a[rel~=nofollow]", "a1"},
+		{"a[href^='https://']", "a1"},
+		{`a[href$="/2"]`, "a2"},
+		{"[href*=a]", "a1"},
+		{"[lang|=vi]", "p3"},
+		{"[data-type=tag i]", "a2"},
+		{"[data-type=tag]", ""},
+		{"li:nth-child(2n+1)", "l1,l3,l5"},
+		{"li:nth-child(even)", "l2,l4"},
+		{"li:nth-last-child(-n+2)", "l4,l5"},
+		{"li:first-child, li:last-child", "l1,l5"},
+		{"#main p:nth-of-type(2)", "p2"},
+		{"li:not(.x):not(:first-child)", "l3,l4,l5"},
+		{"p:has(a, img)", "p2,p3"},
+		{"div:has(> h1)", "#main"},
+		{"h1:has(+ p.lead)", "Tiêu đề"},
+		{"span:empty", "span"},
+		{":root", "html"},
+		{"div :is(h1, .lead)", "Tiêu đề,p1"},
+	} {
+		nodes, err := HTMLQuerySelectorAll(root, c.css)
+		r := cssTestIDs(nodes)
+		if c.expected == "#main" && len(nodes) == 1 {
+			r = "#" + htmlGetAttr(nodes[0], "id")
+		}
+		if err != nil || r != c.expected {
+			t.Errorf("error HTMLQuerySelectorAll %q: real: %v, %v, expected: %v",
+				c.css, r, err, c.expected)
+			continue
+		}
+
+		xPath, err := CSSToXPath(c.css)
+		if err != nil {
+			t.Errorf("error CSSToXPath %q: %v", c.css, err)
+			continue
+		}
+		xNodes, err := HTMLXPath(root, xPath)
+		// union of XPath is not sorted in document order by htmlquery
+		if err != nil || !sameSet(strings.Split(cssTestIDs(xNodes), ","),
+			strings.Split(cssTestIDs(nodes), ",")) {
+			t.Errorf("error CSSToXPath %q: %v: real: %v, %v, expected: %v",
+				c.css, xPath, cssTestIDs(xNodes), err, cssTestIDs(nodes))
+		}
+	}
+
+	first, err := HTMLQuerySelector(root, "li.x ~ li")
+	if err != nil || first == nil || first.FirstChild.Data != "l3" {
+		t.Errorf("error HTMLQuerySelector: %v, %v", first, err)
+	}
+	if none, err := HTMLQuerySelector(root, "table"); none != nil || err != nil {
+		t.Errorf("error HTMLQuerySelector no match: %v, %v", none, err)
+	}
+}
+
+func TestCheckValidCSSSelector(t *testing.T) {
+	for _, valid := range []string{"a", "*", "div > p + ul ~ li", "a[href='x y']",
+		`#a\:b`, "p:nth-child( 2n - 1 )", ":not(a, b c)", "li:has(> a)"} {
+		if err := CheckValidCSSSelector(valid); err != nil {
+			t.Errorf("error CheckValidCSSSelector %q: %v", valid, err)
+		}
+	}
+	for _, invalid := range []string{"", "a >", "a[href", "a[href=]", "p::before",
+		":hover", "p:nth-child(x)", ":not(a", "a,", "#", "div !p"} {
+		if err := CheckValidCSSSelector(invalid); err == nil {
+			t.Errorf("error CheckValidCSSSelector %q: expected an error", invalid)
+		}
+	}
+}
+
+func sameSet(a []string, b []string) bool {
+	sa, sb := toMapStrings(a...), toMapStrings(b...)
+	if len(sa) != len(sb) {
+		return false
+	}
+	for k := range sa {
+		if !sb[k] {
+			return false
+		}
+	}
+	return true
+}
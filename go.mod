@@ -5,6 +5,7 @@ go 1.23.0
 require (
 	github.com/antchfx/htmlquery v1.3.4
 	github.com/antchfx/xpath v1.3.3
+	github.com/rivo/uniseg v0.4.7
 	golang.org/x/net v0.37.0
 	golang.org/x/text v0.23.0
 )
@@ -1,4 +1,4 @@
-for func in TextToWords TextToNGrams RemoveRedundantSpace GenRandomWord; do
+for func in TextToWords TextToNGrams TextToHashedNGrams RemoveRedundantSpace GenRandomWord HTMLXPath; do
     go test -v -run=nomatch -bench="Benchmark${func}" -cpuprofile=cpu_${func}.out
     # break # uncomment if you just want to run the first bench func
 done
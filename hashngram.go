@@ -0,0 +1,104 @@
+package textproc
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// TextToHashedNGrams is TextToNGrams but keys are hashes of n-grams:
+// uint64(HashTextToInt(nGram)) for each nGram in TextToNGrams(text, n).
+// It does not build any string (the text is lowercased on the fly),
+// n must be positive.
+func TextToHashedNGrams(text string, n int) map[uint64]int {
+	result := make(map[uint64]int)
+	ForEachNGram(text, n, func(hash uint64) { result[hash] += 1 })
+	return result
+}
+
+// ForEachNGram calls f with the hash of each n-gram of the text in order,
+// the hash is uint64(HashTextToInt(nGram)) where nGram is an n-gram of
+// TextToNGrams(text, n) (lowercase words joined by a space).
+// It only allocates a buffer of n word positions, n must be positive.
+func ForEachNGram(text string, n int, f func(hash uint64)) {
+	if n <= 0 {
+		return
+	}
+	words := make([][2]int, n) // ring buffer of byte offsets of the last n words
+	count := 0
+	forEachLowerWordSpan(text, func(start int, end int) {
+		words[count%n] = [2]int{start, end}
+		count++
+		if count < n {
+			return
+		}
+		h := uint64(fnvOffset64)
+		for k := 0; k < n; k++ {
+			if k > 0 {
+				h ^= ' '
+				h *= fnvPrime64
+			}
+			word := words[(count+k)%n] // oldest word first
+			h = fnvAddLower(h, text[word[0]:word[1]])
+		}
+		f(h)
+	})
+}
+
+// forEachLowerWordSpan calls f with byte offsets of each word of
+// TextToWords(strings.ToLower(text)), offsets are in the original text
+func forEachLowerWordSpan(text string, f func(start int, end int)) {
+	isAlphaNumeric := func(r rune) bool { return AlphaNumeric[unicode.ToLower(r)] }
+	for i := 0; i < len(text); {
+		char, size := utf8.DecodeRuneInString(text[i:])
+		if checkIsSpaceNL(unicode.ToLower(char)) {
+			i += size
+			continue
+		}
+		// a chunk of non space chars, trim non alphanumeric chars at both ends
+		begin, end := -1, -1
+		j := i
+		for j < len(text) {
+			char, size = utf8.DecodeRuneInString(text[j:])
+			if checkIsSpaceNL(unicode.ToLower(char)) {
+				break
+			}
+			if isAlphaNumeric(char) {
+				if begin == -1 {
+					begin = j
+				}
+				end = j + size
+			}
+			j += size
+		}
+		if begin != -1 {
+			f(begin, end)
+		}
+		i = j
+	}
+}
+
+// fnvAddLower adds the lowercase bytes of s to the FNV-1a hash h,
+// same as hashing the bytes of strings.ToLower(s)
+func fnvAddLower(h uint64, s string) uint64 {
+	var buf [utf8.UTFMax]byte
+	for i := 0; i < len(s); {
+		b := s[i]
+		if b < utf8.RuneSelf {
+			if 'A' <= b && b <= 'Z' {
+				b += 'a' - 'A'
+			}
+			h ^= uint64(b)
+			h *= fnvPrime64
+			i++
+			continue
+		}
+		char, size := utf8.DecodeRuneInString(s[i:])
+		nBytes := utf8.EncodeRune(buf[:], unicode.ToLower(char))
+		for _, b := range buf[:nBytes] {
+			h ^= uint64(b)
+			h *= fnvPrime64
+		}
+		i += size
+	}
+	return h
+}
@@ -0,0 +1,42 @@
+package textproc
+
+import (
+	"testing"
+)
+
+func TestTextToHashedNGrams(t *testing.T) {
+	texts := append([]string{
+		"Thị Trường chứng khoán, THỊ TRƯỜNG chứng khoán!",
+		"  -- a --  B\tc\nd ... ",
+		"ĐÀ NẴNG\xff đà nẵng",
+		"",
+	}, paragraphs...)
+	for _, text := range texts {
+		for n := 1; n <= 3; n++ {
+			nGrams := TextToNGrams(text, n)
+			hashed := TextToHashedNGrams(text, n)
+			if len(hashed) != len(nGrams) {
+				t.Errorf("error TextToHashedNGrams len n=%v: real: %v, expected: %v",
+					n, len(hashed), len(nGrams))
+			}
+			for nGram, count := range nGrams {
+				if r := hashed[uint64(HashTextToInt(nGram))]; r != count {
+					t.Errorf("error TextToHashedNGrams %q: real: %v, expected: %v",
+						nGram, r, count)
+				}
+			}
+		}
+	}
+	if r := TextToHashedNGrams("a b", 0); len(r) != 0 {
+		t.Errorf("error TextToHashedNGrams n=0: %v", r)
+	}
+}
+
+func TestForEachNGram(t *testing.T) {
+	var r []uint64
+	ForEachNGram("A b, c.", 2, func(hash uint64) { r = append(r, hash) })
+	e := []uint64{uint64(HashTextToInt("a b")), uint64(HashTextToInt("b c"))}
+	if len(r) != len(e) || r[0] != e[0] || r[1] != e[1] {
+		t.Errorf("error ForEachNGram: real: %v, expected: %v", r, e)
+	}
+}
@@ -9,30 +9,38 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/antchfx/htmlquery"
 	"github.com/antchfx/xpath"
 	"golang.org/x/net/html"
 )
 
-// HTMLXPath finds all html nodes match the xpath query
+// HTMLXPath finds all html nodes match the xpath query,
+// compiled expressions are cached (LRU), see CompiledXPath
 func HTMLXPath(htmlTree *html.Node, xPath string) ([]*html.Node, error) {
-	xPathObj, err := xpath.Compile(xPath)
+	compiled, err := globalXPathCache.get(xPath)
 	if err != nil {
-		return nil, fmt.Errorf("error xpath Compile: %v", err)
+		return nil, err
 	}
-	nodes := htmlquery.QuerySelectorAll(htmlTree, xPathObj)
-	return nodes, nil
+	return compiled.All(htmlTree), nil
 }
 
 // HTMLGetText returns all text in the HTML.
 // Result does not contain js code or texts that are generated by js.
 // This func is slow, caller should reuse the result if possible.
 func HTMLGetText(node *html.Node) string {
+	return htmlGetText(node, nil)
+}
+
+// htmlGetText is HTMLGetText with an option to skip subtrees,
+// a subtree is skipped if isSkipped(subtreeRoot) is true
+func htmlGetText(node *html.Node, isSkipped func(*html.Node) bool) string {
 	excludedTags := map[string]bool{"script": true, "style": true}
 
 	var buf bytes.Buffer
 	var f func(*html.Node)
 	f = func(n *html.Node) {
+		if isSkipped != nil && isSkipped(n) {
+			return
+		}
 		if n.Type == html.TextNode {
 			isExcluded := false
 			if n.Parent != nil {
@@ -59,22 +67,67 @@ func HTMLGetText(node *html.Node) string {
 	return result
 }
 
+// htmlGetAttr returns value of the attribute key of the node,
+// returns empty string if the node does not have the attribute
+func htmlGetAttr(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// htmlHasAttr returns true if the node has the attribute (value can be empty)
+func htmlHasAttr(node *html.Node, key string) bool {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// hasToken returns true if the space separated list (example: a class or a
+// rel attribute) contains the token. Class names in CSS selectors are case
+// sensitive, rel values are not,
+// example: hasToken("nofollow Canonical", "canonical", false) == true
+func hasToken(list string, token string, caseSensitive bool) bool {
+	if token == "" {
+		return false
+	}
+	for _, t := range strings.Fields(list) {
+		if t == token || !caseSensitive && strings.EqualFold(t, token) {
+			return true
+		}
+	}
+	return false
+}
+
+var xpathHREFs = MustCompileXPath("//a/@href")
+
 // HTMLGetHREFs returns all URLs in the HTML as absolute URLs,
 // URLs with different fragments are treated as one URL.
+// Relative URLs are resolved against the document <base href> (which is
+// resolved against baseUrlStr) if it exists.
 // Silently ignore if baseUrlStr is invalid.
 func HTMLGetHREFs(baseUrlStr string, node *html.Node) []string {
+	return HTMLGetHREFsWithOptions(baseUrlStr, node, LinkOptions{})
+}
+
+// HTMLGetHREFsWithOptions is HTMLGetHREFs with options, URLs that have the
+// same canonical form are treated as one URL if opts.Canonicalize is set
+func HTMLGetHREFsWithOptions(baseUrlStr string, node *html.Node, opts LinkOptions) []string {
 	setUrls := make(map[string]bool)
 	baseUrl, _ := url.Parse(baseUrlStr)
-	elems, _ := HTMLXPath(node, "//a/@href")
-	for _, elem := range elems {
+	baseUrl = htmlDocumentBase(baseUrl, node)
+	for _, elem := range xpathHREFs.All(node) {
 		if elem.FirstChild != nil {
-			relativeUrlStr := elem.FirstChild.Data
-			url0, _ := url.Parse(relativeUrlStr)
-			if baseUrl != nil {
-				url0 = baseUrl.ResolveReference(url0)
+			url0, err := htmlResolveURL(baseUrl, elem.FirstChild.Data)
+			if err != nil {
+				continue
 			}
-			url0.Fragment = ""
-			setUrls[url0.String()] = true
+			setUrls[opts.canonicalize(url0)] = true
 		}
 	}
 
@@ -86,29 +139,39 @@ func HTMLGetHREFs(baseUrlStr string, node *html.Node) []string {
 	return result
 }
 
-// HTMLGetImgSrc returns absolute url of the image
-func HTMLGetImgSrc(baseUrlStr string, imgNode *html.Node) string {
-	var imgSrcS string
-	for _, attr := range imgNode.Attr {
-		if attr.Key == "src" {
-			imgSrcS = attr.Val
-			break
-		}
-	}
-
-	// convert relative url to absolute url
-	baseUrl, _ := url.Parse(baseUrlStr)
-	imgSrc, _ := url.Parse(imgSrcS)
-	if imgSrc == nil {
-		return ""
+// htmlResolveURL converts a relative URL in a HTML to an absolute URL
+// (fragment removed), baseUrl can be nil
+func htmlResolveURL(baseUrl *url.URL, relativeUrlStr string) (string, error) {
+	url0, err := url.Parse(strings.TrimSpace(relativeUrlStr))
+	if err != nil {
+		return "", err
 	}
 	if baseUrl != nil {
-		imgSrc = baseUrl.ResolveReference(imgSrc)
+		url0 = baseUrl.ResolveReference(url0)
 	}
-	if !strings.HasPrefix(imgSrc.String(), "http") {
-		return "" // src can be "data:image/jpeg;base64,ddd"
+	url0.Fragment = ""
+	return url0.String(), nil
+}
+
+// HTMLGetImgSrc returns absolute url of the image.
+// Lazy loading attributes (data-src, data-original, ...) are preferred over
+// src (which is usually a placeholder in that case), the largest candidate in
+// srcset and <picture><source> is used if src is empty or not a http URL.
+// Relative URLs are resolved against the document <base href> if it exists.
+func HTMLGetImgSrc(baseUrlStr string, imgNode *html.Node) string {
+	baseUrl, _ := url.Parse(baseUrlStr)
+	return htmlImgSrc(htmlDocumentBase(baseUrl, imgNode), imgNode)
+}
+
+// htmlImgSrc is HTMLGetImgSrc with a resolved document base
+func htmlImgSrc(base *url.URL, imgNode *html.Node) string {
+	candidates := htmlImageCandidates(base, imgNode)
+	for _, c := range candidates {
+		if !c.FromSrcset {
+			return c.URL
+		}
 	}
-	return imgSrc.String()
+	return pickLargestImage(candidates)
 }
 
 // HTMLParseToNode parses a HTML content (string, []byte or io_Reader) into a
@@ -0,0 +1,237 @@
+package textproc
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// TextOptions configures HTMLToText
+type TextOptions struct {
+	// ExcludedTags are elements that are removed with their content,
+	// nil means DefaultTextExcludedTags
+	ExcludedTags map[string]bool
+	// WrapWidth is the max number of chars in a line, longer lines are
+	// wrapped at spaces (a word longer than WrapWidth is not broken).
+	// 0 means no wrapping, preformatted text and tables are never wrapped.
+	WrapWidth int
+}
+
+// DefaultTextExcludedTags are elements that HTMLToText removes by default
+var DefaultTextExcludedTags = toMapStrings(
+	"head", "script", "style", "noscript", "template", "svg")
+
+// HTMLToText renders a HTML to plain text with the layout of a browser:
+// inline elements (b, a, span, ...) are joined in one line, paragraphs and
+// other blocks are separated by an empty line, list items are prefixed by a
+// bullet or a number, tables are laid out as aligned columns.
+func HTMLToText(node *html.Node, opts TextOptions) string {
+	if opts.ExcludedTags == nil {
+		opts.ExcludedTags = DefaultTextExcludedTags
+	}
+	r := &textRenderer{opts: opts}
+	var blocks []string
+	if node.Type == html.ElementNode && !htmlBlockTags[node.Data] {
+		blocks = r.paragraph(r.inline(node), opts.WrapWidth)
+	} else {
+		blocks = r.block(node, opts.WrapWidth)
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+type textRenderer struct {
+	opts TextOptions
+}
+
+// block renders a block element to text blocks, lines are wrapped at width
+// (0 means no wrapping)
+func (r *textRenderer) block(n *html.Node, width int) []string {
+	if n.Type != html.ElementNode {
+		return r.blocks(n, width)
+	}
+	if r.opts.ExcludedTags[n.Data] {
+		return nil
+	}
+	switch n.Data {
+	case "pre":
+		if text := strings.Trim(htmlRawText(n), "\n"); strings.TrimSpace(text) != "" {
+			return []string{NormalizeText(text)}
+		}
+		return nil
+	case "ul", "ol":
+		if list := r.list(n, width); list != "" {
+			return []string{list}
+		}
+		return nil
+	case "table":
+		if table := r.table(n); table != "" {
+			return []string{table}
+		}
+		return nil
+	}
+	return r.blocks(n, width)
+}
+
+// blocks renders children of a node, consecutive inline children are
+// joined into a paragraph
+func (r *textRenderer) blocks(n *html.Node, width int) []string {
+	ret := make([]string, 0)
+	var inline strings.Builder
+	flush := func() {
+		ret = append(ret, r.paragraph(inline.String(), width)...)
+		inline.Reset()
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && htmlBlockTags[child.Data] {
+			flush()
+			ret = append(ret, r.block(child, width)...)
+			continue
+		}
+		inline.WriteString(r.inline(child))
+	}
+	flush()
+	return ret
+}
+
+// inline renders a node to inline text, line breaks in the result are <br>
+func (r *textRenderer) inline(n *html.Node) string {
+	switch {
+	case n.Type == html.TextNode:
+		return NormalizeText(markdownSpacesRegexp.ReplaceAllString(n.Data, " "))
+	case n.Type == html.ElementNode && r.opts.ExcludedTags[n.Data]:
+		return ""
+	case n.Type == html.ElementNode && n.Data == "br":
+		return "\n"
+	}
+	var buf strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		buf.WriteString(r.inline(child))
+	}
+	if n.Type == html.ElementNode && (htmlBlockTags[n.Data] || n.Data == "td" || n.Data == "th") {
+		return " " + buf.String() + " " // a block inside an inline element
+	}
+	return buf.String()
+}
+
+// paragraph collapses spaces of the inline text and wraps its lines,
+// returns nil if the paragraph is empty
+func (r *textRenderer) paragraph(inline string, width int) []string {
+	p := markdownParagraph(inline, "\n")
+	if p == "" {
+		return nil
+	}
+	if width > 0 {
+		lines := make([]string, 0)
+		for _, line := range strings.Split(p, "\n") {
+			lines = append(lines, wrapLine(line, width)...)
+		}
+		p = strings.Join(lines, "\n")
+	}
+	return []string{p}
+}
+
+// list renders an ul or ol element, nested lists are indented
+func (r *textRenderer) list(n *html.Node, width int) string {
+	number := 1
+	if start, err := strconv.Atoi(htmlGetAttr(n, "start")); err == nil {
+		number = start
+	}
+	items := make([]string, 0)
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode || li.Data != "li" {
+			continue
+		}
+		marker := "- "
+		if n.Data == "ol" {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+		contentWidth := 0
+		if width > 0 {
+			contentWidth = max(width-len(marker), 1)
+		}
+		content := strings.Join(r.blocks(li, contentWidth), "\n")
+		indent := strings.Repeat(" ", len(marker))
+		items = append(items, marker+strings.TrimPrefix(
+			markdownPrefixLines(content, indent, ""), indent))
+	}
+	return strings.Join(items, "\n")
+}
+
+// table renders a table element as aligned columns
+func (r *textRenderer) table(n *html.Node) string {
+	rows := make([][]string, 0)
+	var f func(*html.Node)
+	f = func(node *html.Node) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			switch child.Data {
+			case "thead", "tbody", "tfoot":
+				f(child)
+			case "tr":
+				row := make([]string, 0)
+				for cell := child.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.Type == html.ElementNode && (cell.Data == "td" || cell.Data == "th") {
+						row = append(row, markdownParagraph(r.inline(cell), " "))
+					}
+				}
+				rows = append(rows, row)
+			}
+		}
+	}
+	f(n)
+	widths := make([]int, 0)
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		var line strings.Builder
+		for i, cell := range row {
+			if i > 0 {
+				line.WriteString("  ")
+			}
+			line.WriteString(cell)
+			line.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+		}
+		if l := strings.TrimRight(line.String(), " "); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrapLine splits a line at spaces so each line has at most width chars,
+// a word longer than width is put in its own line
+func wrapLine(line string, width int) []string {
+	ret := make([]string, 0)
+	var current strings.Builder
+	currentLen := 0
+	for _, word := range strings.Fields(line) {
+		wordLen := utf8.RuneCountInString(word)
+		if currentLen > 0 && currentLen+1+wordLen > width {
+			ret = append(ret, current.String())
+			current.Reset()
+			currentLen = 0
+		}
+		if currentLen > 0 {
+			current.WriteString(" ")
+			currentLen++
+		}
+		current.WriteString(word)
+		currentLen += wordLen
+	}
+	if currentLen > 0 {
+		ret = append(ret, current.String())
+	}
+	return ret
+}
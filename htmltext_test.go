@@ -0,0 +1,46 @@
+package textproc
+
+import (
+	"testing"
+)
+
+func TestHTMLToText(t *testing.T) {
+	root := HTMLParseToNode(`<html><head><title>Tiêu đề trang</title></head><body>
+<h1>Tiêu đề</h1>
+<p>Đoạn <b>đậm</b> và <a href="/x">liên kết</a>.<br>Dòng mới</p>
+<div><div>Khối lồng nhau</div></div>
+<ul><li>Một</li><li>Hai<ol><li>Hai một</li><li>Hai hai</li></ol></li></ul>
+<table><tr><th>Tên</th><th>Giá</th></tr><tr><td>Cà phê sữa</td><td>25.000</td></tr></table>
+<pre>  x := 1
+  y := 2</pre>
+<script>var a = 1;</script><style>p {}</style>
+</body></html>`)
+	r := HTMLToText(root, TextOptions{})
+	e := "Tiêu đề\n\n" +
+		"Đoạn đậm và liên kết.\nDòng mới\n\n" +
+		"Khối lồng nhau\n\n" +
+		"- Một\n- Hai\n  1. Hai một\n  2. Hai hai\n\n" +
+		"Tên         Giá\nCà phê sữa  25.000\n\n" +
+		"  x := 1\n  y := 2"
+	if r != e {
+		t.Errorf("error HTMLToText: real:\n%v\nexpected:\n%v", r, e)
+	}
+
+	r = HTMLToText(root, TextOptions{ExcludedTags: toMapStrings("table", "pre", "ul")})
+	e = "Tiêu đề trang\n\nTiêu đề\n\n" +
+		"Đoạn đậm và liên kết.\nDòng mới\n\nKhối lồng nhau\n\nvar a = 1;p {}"
+	if r != e {
+		t.Errorf("error HTMLToText ExcludedTags: real:\n%v\nexpected:\n%v", r, e)
+	}
+}
+
+func TestHTMLToTextWrap(t *testing.T) {
+	root := HTMLParseToNode(`<p>Hôm nay trời đẹp, chúng tôi đi dạo quanh hồ Gươm.</p>
+<ul><li>một hai ba bốn năm sáu bảy</li></ul>`)
+	r := HTMLToText(root, TextOptions{WrapWidth: 16})
+	e := "Hôm nay trời\nđẹp, chúng tôi\nđi dạo quanh hồ\nGươm.\n\n" +
+		"- một hai ba bốn\n  năm sáu bảy"
+	if r != e {
+		t.Errorf("error HTMLToText WrapWidth: real:\n%v\nexpected:\n%v", r, e)
+	}
+}
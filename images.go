@@ -0,0 +1,163 @@
+package textproc
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// ImageCandidate is an URL of an image, from src, srcset or a lazy loading
+// attribute of an <img> or from a <picture><source>
+type ImageCandidate struct {
+	URL string // absolute URL
+	// Width is the "w" descriptor in srcset, 0 if not specified
+	Width int
+	// Density is the "x" descriptor in srcset, 0 if not specified
+	Density float64
+	// Source is the attribute that contains the URL,
+	// example: "src", "data-src", "srcset", "source[srcset]"
+	Source string
+	// FromSrcset is true if the URL is from a srcset of <img> or <source>
+	FromSrcset bool
+}
+
+// Image is a result of HTMLGetImages
+type Image struct {
+	// URL is the largest candidate
+	URL        string
+	Alt        string
+	Candidates []ImageCandidate
+}
+
+// imgLazyAttrs are attributes that lazy loading scripts use for the real
+// image URL, ordered by priority
+var imgLazyAttrs = []string{"data-src", "data-original", "data-lazy-src", "data-url"}
+
+// imgSrcsetAttrs are srcset attributes of <img> and <source>
+var imgSrcsetAttrs = []string{"srcset", "data-srcset", "data-lazy-srcset"}
+
+// HTMLGetImages returns all images (<img>) in the HTML in document order,
+// each image has all candidate URLs (src, srcset, lazy loading attributes,
+// <picture><source>) and the largest candidate.
+// Relative URLs are resolved against the document <base href> (which is
+// resolved against baseURL). Images without a http URL are ignored.
+func HTMLGetImages(baseURL string, node *html.Node) []Image {
+	pageURL, _ := url.Parse(baseURL)
+	base := htmlDocumentBase(pageURL, node)
+	ret := make([]Image, 0)
+	imgs, _ := HTMLXPath(node, `//img`)
+	for _, img := range imgs {
+		candidates := htmlImageCandidates(base, img)
+		if len(candidates) == 0 {
+			continue
+		}
+		ret = append(ret, Image{
+			URL:        pickLargestImage(candidates),
+			Alt:        strings.Join(strings.Fields(NormalizeText(htmlGetAttr(img, "alt"))), " "),
+			Candidates: candidates,
+		})
+	}
+	return ret
+}
+
+// htmlImageCandidates returns http URLs of the img node, ordered by:
+// lazy loading attributes, src, srcset, <picture><source>
+func htmlImageCandidates(base *url.URL, img *html.Node) []ImageCandidate {
+	ret := make([]ImageCandidate, 0)
+	add := func(rawURL string, width int, density float64, source string, fromSrcset bool) {
+		if rawURL == "" {
+			return
+		}
+		absURL, err := htmlResolveURL(base, rawURL)
+		if err != nil || !strings.HasPrefix(absURL, "http") {
+			return // src can be "data:image/jpeg;base64,ddd"
+		}
+		ret = append(ret, ImageCandidate{URL: absURL, Width: width,
+			Density: density, Source: source, FromSrcset: fromSrcset})
+	}
+	for _, attr := range imgLazyAttrs {
+		add(htmlGetAttr(img, attr), 0, 0, attr, false)
+	}
+	add(htmlGetAttr(img, "src"), 0, 0, "src", false)
+	addSrcset := func(n *html.Node) {
+		for _, attr := range imgSrcsetAttrs {
+			source := attr
+			if n.Data == "source" {
+				source = "source[" + attr + "]"
+			}
+			for _, c := range parseSrcset(htmlGetAttr(n, attr)) {
+				add(c.URL, c.Width, c.Density, source, true)
+			}
+		}
+	}
+	addSrcset(img)
+	if img.Parent != nil && img.Parent.Type == html.ElementNode &&
+		img.Parent.Data == "picture" {
+		for c := img.Parent.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && c.Data == "source" {
+				addSrcset(c)
+			}
+		}
+	}
+	return ret
+}
+
+// pickLargestImage returns URL of the candidate that has the largest width
+// descriptor, or the largest density descriptor if no candidate has width.
+// The first candidate is returned if no candidate has descriptors.
+func pickLargestImage(candidates []ImageCandidate) string {
+	if len(candidates) == 0 {
+		return ""
+	}
+	best := candidates[0]
+	for _, c := range candidates[1:] {
+		if c.Width > best.Width ||
+			c.Width == best.Width && c.Density > best.Density {
+			best = c
+		}
+	}
+	return best.URL
+}
+
+// parseSrcset parses a srcset attribute, example:
+// "a.jpg 480w, b.jpg 800w" or "a.jpg, b.jpg 2x", URLs are not resolved
+func parseSrcset(srcset string) []ImageCandidate {
+	ret := make([]ImageCandidate, 0)
+	s := srcset
+	for {
+		s = strings.TrimLeftFunc(s, func(r rune) bool { return unicode.IsSpace(r) || r == ',' })
+		if s == "" {
+			return ret
+		}
+		// URL is a run of non space chars, trailing commas are not part of it
+		end := strings.IndexFunc(s, unicode.IsSpace)
+		if end == -1 {
+			end = len(s)
+		}
+		rawURL := s[:end]
+		s = s[end:]
+		var descriptors string
+		if strings.HasSuffix(rawURL, ",") {
+			rawURL = strings.TrimRight(rawURL, ",")
+		} else {
+			comma := strings.Index(s, ",")
+			if comma == -1 {
+				comma = len(s)
+			}
+			descriptors, s = s[:comma], s[comma:]
+		}
+		c := ImageCandidate{URL: rawURL}
+		for _, d := range strings.Fields(descriptors) {
+			switch {
+			case strings.HasSuffix(d, "w"):
+				c.Width, _ = strconv.Atoi(strings.TrimSuffix(d, "w"))
+			case strings.HasSuffix(d, "x"):
+				c.Density, _ = strconv.ParseFloat(strings.TrimSuffix(d, "x"), 64)
+			}
+		}
+		ret = append(ret, c)
+	}
+}
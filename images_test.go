@@ -0,0 +1,65 @@
+package textproc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSrcset(t *testing.T) {
+	r := parseSrcset(" a.jpg 480w,b.jpg 800w , https://c.com/w_100,h_100/c.jpg 2x, d.jpg")
+	e := []ImageCandidate{
+		{URL: "a.jpg", Width: 480},
+		{URL: "b.jpg", Width: 800},
+		{URL: "https://c.com/w_100,h_100/c.jpg", Density: 2},
+		{URL: "d.jpg"},
+	}
+	if !reflect.DeepEqual(r, e) {
+		t.Errorf("error parseSrcset: real: %+v, expected: %+v", r, e)
+	}
+}
+
+func TestHTMLGetImages(t *testing.T) {
+	page := `<html><head><base href="https://cdn.example.com/img/"></head><body>
+<img src="blank.gif" data-src="lazy.jpg" alt="Ảnh  lazy">
+<picture>
+  <source srcset="p-800.webp 800w, p-1600.webp 1600w" type="image/webp">
+  <img src="p.jpg" srcset="p-400.jpg 400w, p-1200.jpg 1200w" alt="Picture">
+</picture>
+<img src="data:image/gif;base64,R0lGODlhAQABAAAAACw=">
+<img src="/logo.png" srcset="/logo@2x.png 2x">
+</body></html>`
+	root := HTMLParseToNode(page)
+	images := HTMLGetImages("https://example.com/news/1.html", root)
+	if len(images) != 3 {
+		t.Fatalf("error HTMLGetImages: %+v", images)
+	}
+	if images[0].URL != "https://cdn.example.com/img/lazy.jpg" || images[0].Alt != "Ảnh lazy" || len(images[0].Candidates) != 2 {
+		t.Errorf("error HTMLGetImages lazy: %+v", images[0])
+	}
+	if images[1].URL != "https://cdn.example.com/img/p-1600.webp" ||
+		len(images[1].Candidates) != 5 ||
+		images[1].Candidates[4].Source != "source[srcset]" || !images[1].Candidates[4].FromSrcset ||
+		images[1].Candidates[0].FromSrcset {
+		t.Errorf("error HTMLGetImages picture: %+v", images[1])
+	}
+	if images[2].URL != "https://cdn.example.com/logo@2x.png" {
+		t.Errorf("error HTMLGetImages density: %+v", images[2])
+	}
+
+	imgs, _ := HTMLXPath(root, "//img")
+	for i, e := range []string{
+		"https://cdn.example.com/img/lazy.jpg",
+		"https://cdn.example.com/img/p.jpg",
+		"",
+		"https://cdn.example.com/logo.png",
+	} {
+		if r := HTMLGetImgSrc("https://example.com/news/1.html", imgs[i]); r != e {
+			t.Errorf("error HTMLGetImgSrc %v: real: %v, expected: %v", i, r, e)
+		}
+	}
+	urls := HTMLGetHREFs("https://example.com/news/1.html",
+		HTMLParseToNode(`<base href="/vi/"><a href="x.html">x</a>`))
+	if len(urls) != 1 || urls[0] != "https://example.com/vi/x.html" {
+		t.Errorf("error HTMLGetHREFs with base: %v", urls)
+	}
+}
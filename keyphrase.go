@@ -0,0 +1,239 @@
+package textproc
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// TextSpan is a part of a text, Start and End are byte offsets
+// (text[Start:End] is the part)
+type TextSpan struct {
+	Start int
+	End   int
+}
+
+// Keyphrase is a result of keyphrase extraction
+type Keyphrase struct {
+	Text    string // lowercase words separated by a space
+	Score   float64
+	Offsets []TextSpan // all occurrences of the phrase in the text
+}
+
+// KeyphraseOptions configures ExtractKeyphrasesRAKE and
+// ExtractKeyphrasesTextRank, zero value is usable
+type KeyphraseOptions struct {
+	// StopWords split candidate phrases,
+	// default is VietnameseStopWords merged with EnglishStopWords
+	StopWords StopWords
+	// MaxWords is the max number of words in a phrase,
+	// default is 0 (unlimited) for RAKE and 3 for TextRank
+	MaxWords int
+	// TopK is the max number of returned phrases, default is 0 (all)
+	TopK int
+	// Window is the co-occurrence window size of TextRank, default is 2
+	Window int
+}
+
+var defaultKeyphraseStopWords = VietnameseStopWords.Merge(EnglishStopWords)
+
+// candidatePhrase is a sequence of candidate words that does not
+// cross punctuations
+type candidatePhrase struct {
+	words []string // lowercase
+	span  TextSpan
+}
+
+// extractCandidatePhrases splits the text by punctuations and words that
+// are not accepted by isCandidate (input of isCandidate is lowercase)
+func extractCandidatePhrases(text string, isCandidate func(string) bool,
+	maxWords int) []candidatePhrase {
+	ret := make([]candidatePhrase, 0)
+	var current *candidatePhrase
+	for _, ws := range textToWordSpans(text) {
+		lower := strings.ToLower(ws.word)
+		accepted := isCandidate(lower)
+		if ws.boundary || !accepted ||
+			(current != nil && maxWords > 0 && len(current.words) >= maxWords) {
+			if current != nil {
+				ret = append(ret, *current)
+				current = nil
+			}
+		}
+		if !accepted {
+			continue
+		}
+		if current == nil {
+			current = &candidatePhrase{span: TextSpan{Start: ws.start}}
+		}
+		current.words = append(current.words, lower)
+		current.span.End = ws.end
+	}
+	if current != nil {
+		ret = append(ret, *current)
+	}
+	return ret
+}
+
+// scorePhrases sums word scores for each distinct phrase,
+// returns phrases sorted by score
+func scorePhrases(phrases []candidatePhrase, wordScores map[string]float64,
+	topK int) []Keyphrase {
+	byText := make(map[string]*Keyphrase)
+	for _, phrase := range phrases {
+		text := strings.Join(phrase.words, " ")
+		kp, found := byText[text]
+		if !found {
+			kp = &Keyphrase{Text: text}
+			for _, word := range phrase.words {
+				kp.Score += wordScores[word]
+			}
+			byText[text] = kp
+		}
+		kp.Offsets = append(kp.Offsets, phrase.span)
+	}
+	ret := make([]Keyphrase, 0, len(byText))
+	for _, kp := range byText {
+		ret = append(ret, *kp)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Score != ret[j].Score {
+			return ret[i].Score > ret[j].Score
+		}
+		return ret[i].Text < ret[j].Text
+	})
+	if topK > 0 && len(ret) > topK {
+		ret = ret[:topK]
+	}
+	return ret
+}
+
+// ExtractKeyphrasesRAKE returns keyphrases of the text by Rapid Automatic
+// Keyword Extraction: candidate phrases are split by stop words and
+// punctuations, a word score is its degree (sum of lengths of phrases that
+// contain the word) divided by its frequency, a phrase score is sum of
+// its word scores.
+func ExtractKeyphrasesRAKE(text string, opts KeyphraseOptions) []Keyphrase {
+	sw := opts.StopWords
+	if sw == nil {
+		sw = defaultKeyphraseStopWords
+	}
+	isCandidate := func(word string) bool { return !sw.Contains(word) }
+	phrases := extractCandidatePhrases(text, isCandidate, opts.MaxWords)
+	freq := make(map[string]int)
+	degree := make(map[string]int)
+	for _, phrase := range phrases {
+		for _, word := range phrase.words {
+			freq[word]++
+			degree[word] += len(phrase.words)
+		}
+	}
+	wordScores := make(map[string]float64, len(freq))
+	for word, f := range freq {
+		wordScores[word] = float64(degree[word]) / float64(f)
+	}
+	return scorePhrases(phrases, wordScores, opts.TopK)
+}
+
+// ExtractKeyphrasesTextRank returns keyphrases of the text by TextRank:
+// words that are not stop words are graph vertices, edge weight is number
+// of times 2 words co-occur within a window, word scores are computed by
+// weighted PageRank.
+// The top third of words are keywords, adjacent keywords are collapsed
+// into a keyphrase, a keyphrase score is sum of its word scores.
+func ExtractKeyphrasesTextRank(text string, opts KeyphraseOptions) []Keyphrase {
+	sw := opts.StopWords
+	if sw == nil {
+		sw = defaultKeyphraseStopWords
+	}
+	maxWords := opts.MaxWords
+	if maxWords <= 0 {
+		maxWords = 3
+	}
+	window := opts.Window
+	if window < 2 {
+		window = 2
+	}
+
+	// build co-occurrence graph, a window does not cross punctuations,
+	// stop words are kept in the window to preserve word distance
+	graph := make(map[string]map[string]float64)
+	spans := textToWordSpans(text)
+	lowers := make([]string, len(spans))
+	for i, ws := range spans {
+		lowers[i] = strings.ToLower(ws.word)
+	}
+	for i := range spans {
+		if sw.Contains(lowers[i]) {
+			continue
+		}
+		if graph[lowers[i]] == nil {
+			graph[lowers[i]] = make(map[string]float64)
+		}
+		for j := i + 1; j < len(spans) && j < i+window; j++ {
+			if spans[j].boundary {
+				break
+			}
+			if sw.Contains(lowers[j]) || lowers[j] == lowers[i] {
+				continue
+			}
+			if graph[lowers[j]] == nil {
+				graph[lowers[j]] = make(map[string]float64)
+			}
+			graph[lowers[i]][lowers[j]]++
+			graph[lowers[j]][lowers[i]]++
+		}
+	}
+
+	wordScores := pageRank(graph, 0.85, 50, 1e-6)
+
+	// the top third of words are keywords,
+	// adjacent keywords are collapsed into a keyphrase
+	ranked := make([]string, 0, len(wordScores))
+	for word := range wordScores {
+		ranked = append(ranked, word)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if wordScores[ranked[i]] != wordScores[ranked[j]] {
+			return wordScores[ranked[i]] > wordScores[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+	keywords := make(map[string]bool)
+	for _, word := range ranked[:(len(ranked)+2)/3] {
+		keywords[word] = true
+	}
+	isCandidate := func(word string) bool { return keywords[word] }
+	phrases := extractCandidatePhrases(text, isCandidate, maxWords)
+	return scorePhrases(phrases, wordScores, opts.TopK)
+}
+
+// pageRank computes scores of vertices in an undirected weighted graph
+func pageRank(graph map[string]map[string]float64, damping float64,
+	maxIterations int, tolerance float64) map[string]float64 {
+	scores := make(map[string]float64, len(graph))
+	sumWeights := make(map[string]float64, len(graph))
+	for v, neighbors := range graph {
+		scores[v] = 1
+		for _, w := range neighbors {
+			sumWeights[v] += w
+		}
+	}
+	for iter := 0; iter < maxIterations; iter++ {
+		next := make(map[string]float64, len(graph))
+		maxDiff := 0.0
+		for v, neighbors := range graph {
+			sum := 0.0
+			for u, w := range neighbors {
+				sum += w / sumWeights[u] * scores[u]
+			}
+			next[v] = (1 - damping) + damping*sum
+			maxDiff = math.Max(maxDiff, math.Abs(next[v]-scores[v]))
+		}
+		scores = next
+		if maxDiff < tolerance {
+			break
+		}
+	}
+	return scores
+}
@@ -0,0 +1,47 @@
+package textproc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractKeyphrasesRAKE(t *testing.T) {
+	text := `Compatibility of systems of linear constraints over the set of natural numbers.
+Criteria of compatibility of a system of linear Diophantine equations, strict
+inequations, and nonstrict inequations are considered.`
+	phrases := ExtractKeyphrasesRAKE(text, KeyphraseOptions{TopK: 3})
+	if len(phrases) != 3 ||
+		phrases[0].Text != "linear diophantine equations" ||
+		phrases[1].Text != "linear constraints" ||
+		phrases[2].Text != "natural numbers" {
+		t.Fatalf("error ExtractKeyphrasesRAKE: %+v", phrases)
+	}
+	for _, phrase := range phrases {
+		for _, offset := range phrase.Offsets {
+			if r := text[offset.Start:offset.End]; strings.ToLower(r) != phrase.Text {
+				t.Errorf("error Keyphrase offset: real: %q, expected: %q", r, phrase.Text)
+			}
+		}
+	}
+}
+
+func TestExtractKeyphrasesTextRank(t *testing.T) {
+	text := `Giá vàng tiếp tục tăng, lập đỉnh lịch sử mới nhờ USD suy yếu.
+Giá vàng giao ngay tại sàn New York tăng 20,1 USD lên 2.039,5 USD/ounce.
+Giá vàng tương lai tăng 1,4% lên 2.049,3 USD/ounce.
+Giá bạc tăng 4,3% lên 27,13 USD/ounce, cao nhất kể từ tháng 4/2013.`
+	phrases := ExtractKeyphrasesTextRank(text, KeyphraseOptions{TopK: 5})
+	if len(phrases) != 5 {
+		t.Fatalf("error ExtractKeyphrasesTextRank: %+v", phrases)
+	}
+	found := false
+	for _, phrase := range phrases {
+		if phrase.Text == "giá vàng" && len(phrase.Offsets) == 3 &&
+			text[phrase.Offsets[1].Start:phrase.Offsets[1].End] == "Giá vàng" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("error ExtractKeyphrasesTextRank: %+v", phrases)
+	}
+}
@@ -0,0 +1,184 @@
+package textproc
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// LinkKind is the element that contains a link
+type LinkKind string
+
+// Link kinds
+const (
+	LinkKindA      LinkKind = "a"
+	LinkKindArea   LinkKind = "area"
+	LinkKindLink   LinkKind = "link"
+	LinkKindIframe LinkKind = "iframe"
+	LinkKindForm   LinkKind = "form"
+)
+
+// linkAttrs maps an element to its URL attribute
+var linkAttrs = map[string]string{
+	"a": "href", "area": "href", "link": "href", "iframe": "src", "form": "action",
+}
+
+// Link is a result of HTMLGetLinks
+type Link struct {
+	URL    string // absolute URL (fragment removed)
+	Text   string // anchor text, alt of area, title of iframe
+	Title  string
+	Rel    []string // lowercase, example: ["nofollow", "ugc"]
+	Target string
+	Kind   LinkKind
+	// Internal is true if the link host is the same as the base host
+	Internal bool
+}
+
+// HasRel returns true if the link has the rel value, example: "nofollow"
+func (l Link) HasRel(rel string) bool {
+	for _, r := range l.Rel {
+		if r == strings.ToLower(rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// LinkOptions configures HTMLGetHREFsWithOptions and HTMLGetLinksWithOptions
+type LinkOptions struct {
+	// Canonicalize is applied to absolute http(s) URLs if it is not nil,
+	// see CanonicalizeURL
+	Canonicalize *CanonicalizeOptions
+}
+
+// canonicalize returns the canonical form of the absolute URL if
+// opts.Canonicalize is set, other URLs (mailto:, ...) are returned as is
+func (opts LinkOptions) canonicalize(absURL string) string {
+	if opts.Canonicalize == nil {
+		return absURL
+	}
+	u, err := url.Parse(absURL)
+	if err != nil || u.Host == "" ||
+		!strings.EqualFold(u.Scheme, "http") && !strings.EqualFold(u.Scheme, "https") {
+		return absURL
+	}
+	canonicalizeURL(u, *opts.Canonicalize)
+	return u.String()
+}
+
+// HTMLGetLinks returns all links (a, area, link, iframe, form action) in
+// the HTML in document order. Relative URLs are resolved against the
+// document <base href> (which is resolved against baseURL), same as
+// HTMLGetHREFs.
+// Silently ignore if baseURL is invalid.
+func HTMLGetLinks(baseURL string, node *html.Node) []Link {
+	return HTMLGetLinksWithOptions(baseURL, node, LinkOptions{})
+}
+
+// HTMLGetLinksWithOptions is HTMLGetLinks with options,
+// example: canonicalize link URLs
+func HTMLGetLinksWithOptions(baseURL string, node *html.Node, opts LinkOptions) []Link {
+	pageURL, _ := url.Parse(baseURL)
+	base := htmlDocumentBase(pageURL, node)
+	baseHost := ""
+	if pageURL != nil && pageURL.Host != "" {
+		baseHost = pageURL.Hostname()
+	} else if base != nil {
+		baseHost = base.Hostname()
+	}
+
+	ret := make([]Link, 0)
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if attrKey, found := linkAttrs[n.Data]; found && htmlHasAttr(n, attrKey) {
+				if link, ok := newLink(base, baseHost, n, attrKey); ok {
+					link.URL = opts.canonicalize(link.URL)
+					ret = append(ret, link)
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+	f(node)
+	return ret
+}
+
+func newLink(base *url.URL, baseHost string, n *html.Node, attrKey string) (Link, bool) {
+	absURL, err := htmlResolveURL(base, htmlGetAttr(n, attrKey))
+	if err != nil {
+		return Link{}, false
+	}
+	link := Link{
+		URL:    absURL,
+		Title:  strings.TrimSpace(htmlGetAttr(n, "title")),
+		Rel:    strings.Fields(strings.ToLower(htmlGetAttr(n, "rel"))),
+		Target: htmlGetAttr(n, "target"),
+		Kind:   LinkKind(n.Data),
+	}
+	switch link.Kind {
+	case LinkKindA:
+		// text nodes are joined directly, inline tags do not split words
+		link.Text = NormalizeText(strings.Join(strings.Fields(htmlRawText(n)), " "))
+	case LinkKindArea:
+		link.Text = NormalizeText(strings.TrimSpace(htmlGetAttr(n, "alt")))
+	case LinkKindIframe:
+		link.Text = link.Title
+	}
+	if u, err := url.Parse(absURL); err == nil && baseHost != "" &&
+		(u.Scheme == "http" || u.Scheme == "https") {
+		link.Internal = strings.EqualFold(u.Hostname(), baseHost)
+	}
+	return link, true
+}
+
+// htmlDocumentBase returns the base URL to resolve relative URLs in the
+// document: the first <base href> in <head> resolved against pageURL, or
+// pageURL if the document does not have a base element. pageURL can be nil,
+// node can be any node in the document.
+func htmlDocumentBase(pageURL *url.URL, node *html.Node) *url.URL {
+	if node == nil {
+		return pageURL
+	}
+	root := node
+	for root.Parent != nil {
+		root = root.Parent
+	}
+	// base element must be in head, so only search: document > html > head
+	var baseHref string
+	var f func(n *html.Node, depth int) bool
+	f = func(n *html.Node, depth int) bool {
+		if n.Type == html.ElementNode && n.Data == "base" && htmlHasAttr(n, "href") {
+			baseHref = htmlGetAttr(n, "href")
+			return true
+		}
+		if depth >= 3 {
+			return false
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && c.Data != "html" && c.Data != "head" &&
+				c.Data != "base" {
+				continue
+			}
+			if f(c, depth+1) {
+				return true
+			}
+		}
+		return false
+	}
+	if !f(root, 0) {
+		return pageURL
+	}
+	base, err := url.Parse(strings.TrimSpace(baseHref))
+	if err != nil {
+		return pageURL
+	}
+	if pageURL != nil {
+		return pageURL.ResolveReference(base)
+	}
+	return base
+}
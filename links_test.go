@@ -0,0 +1,42 @@
+package textproc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHTMLGetLinks(t *testing.T) {
+	page := `<html><head>
+<base href="/vi/">
+<link rel="stylesheet" href="style.css">
+</head><body>
+<a href="tin-tuc#top" title="News">Tin <b>tức</b></a>
+<a href="https://Other.com/x" rel="nofollow UGC" target="_blank">Other</a>
+<map><area href="/map" alt="Bản đồ"></map>
+<iframe src="//www.youtube.com/embed/1" title="Video"></iframe>
+<form action="search"></form>
+<a name="no-href">anchor without href</a>
+</body></html>`
+	links := HTMLGetLinks("https://example.com/page.html", HTMLParseToNode(page))
+	expected := []Link{
+		{URL: "https://example.com/vi/style.css", Rel: []string{"stylesheet"}, Kind: LinkKindLink, Internal: true},
+		{URL: "https://example.com/vi/tin-tuc", Text: "Tin tức", Title: "News", Rel: []string{}, Kind: LinkKindA, Internal: true},
+		{URL: "https://Other.com/x", Text: "Other", Rel: []string{"nofollow", "ugc"}, Target: "_blank", Kind: LinkKindA},
+		{URL: "https://example.com/map", Text: "Bản đồ", Rel: []string{}, Kind: LinkKindArea, Internal: true},
+		{URL: "https://www.youtube.com/embed/1", Text: "Video", Title: "Video", Rel: []string{}, Kind: LinkKindIframe},
+		{URL: "https://example.com/vi/search", Rel: []string{}, Kind: LinkKindForm, Internal: true},
+	}
+	if !reflect.DeepEqual(links, expected) {
+		t.Errorf("error HTMLGetLinks:\nreal:     %+v\nexpected: %+v", links, expected)
+	}
+	if !links[2].HasRel("NoFollow") || links[1].HasRel("nofollow") {
+		t.Errorf("error Link HasRel")
+	}
+
+	links = HTMLGetLinks("https://example.com/", HTMLParseToNode(
+		`<a href="/x">Tin<b>tức</b>  <i>mới</i>
+<br>nhất</a>`))
+	if len(links) != 1 || links[0].Text != "Tintức mới nhất" {
+		t.Errorf("error HTMLGetLinks inline tags: %+v", links)
+	}
+}
@@ -0,0 +1,427 @@
+package textproc
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// MarkdownOptions configures HTMLToMarkdown
+type MarkdownOptions struct {
+	// DropImages removes all images from the result
+	DropImages bool
+	// ReferenceLinks writes links as "[text][1]" and lists the URLs at the
+	// end of the result ("[1]: https://..."), instead of inline "[text](url)"
+	ReferenceLinks bool
+}
+
+var (
+	// htmlBlockTags are elements that start a new block in HTMLToMarkdown and
+	// HTMLToText
+	htmlBlockTags = map[string]bool{
+		"address": true, "article": true, "aside": true, "blockquote": true,
+		"body": true, "dd": true, "details": true, "div": true, "dl": true,
+		"dt": true, "fieldset": true, "figcaption": true, "figure": true,
+		"footer": true, "form": true, "h1": true, "h2": true, "h3": true,
+		"h4": true, "h5": true, "h6": true, "header": true, "hr": true,
+		"html": true, "li": true, "main": true, "nav": true, "ol": true,
+		"p": true, "pre": true, "section": true, "summary": true,
+		"table": true, "ul": true,
+	}
+	// markdownSkippedTags are elements that are not converted to Markdown
+	markdownSkippedTags = map[string]bool{
+		"head": true, "script": true, "style": true, "noscript": true,
+		"template": true, "svg": true, "button": true, "input": true,
+		"select": true, "textarea": true,
+	}
+	markdownSpacesRegexp    = regexp.MustCompile(`[ \t\r\n\f]+`)
+	markdownLineStartRegexp = regexp.MustCompile(`^(#{1,6}|[-+>]|\d+[.)])( |$)`)
+	markdownEscaper         = strings.NewReplacer(`\`, `\\`, "*", `\*`,
+		"_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`)
+	markdownURLEscaper = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29")
+)
+
+// HTMLToMarkdown converts a HTML to CommonMark (with GFM tables and
+// strikethrough): headings, paragraphs, emphasis, links, images, lists,
+// blockquotes, fenced code blocks and tables are kept.
+// Link and image URLs are resolved to absolute form in the same way as
+// HTMLGetHREFs and HTMLGetImgSrc.
+func HTMLToMarkdown(baseURL string, node *html.Node, opts MarkdownOptions) string {
+	pageURL, _ := url.Parse(baseURL)
+	c := &markdownConverter{
+		base:     htmlDocumentBase(pageURL, node),
+		opts:     opts,
+		refIndex: make(map[string]int),
+	}
+	var blocks []string
+	if node.Type == html.ElementNode && !htmlBlockTags[node.Data] {
+		if p := markdownParagraph(c.inline(node), "  \n"); p != "" {
+			blocks = []string{markdownEscapeLineStart(p)}
+		}
+	} else {
+		blocks = c.block(node)
+	}
+	ret := strings.Join(blocks, "\n\n")
+	if len(c.refs) > 0 {
+		ret += "\n\n" + strings.Join(c.refs, "\n")
+	}
+	return strings.TrimSpace(ret)
+}
+
+type markdownConverter struct {
+	base     *url.URL
+	opts     MarkdownOptions
+	refs     []string       // reference link definitions
+	refIndex map[string]int // link destination to reference number
+}
+
+// block converts a block element to Markdown blocks
+func (c *markdownConverter) block(n *html.Node) []string {
+	if n.Type != html.ElementNode {
+		return c.blocks(n)
+	}
+	if markdownSkippedTags[n.Data] {
+		return nil
+	}
+	switch n.Data {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		text := markdownParagraph(c.inline(n), " ")
+		if text == "" {
+			return nil
+		}
+		return []string{strings.Repeat("#", int(n.Data[1]-'0')) + " " + text}
+	case "hr":
+		return []string{"---"}
+	case "pre":
+		return []string{markdownCodeBlock(n)}
+	case "blockquote":
+		inner := strings.Join(c.blocks(n), "\n\n")
+		if inner == "" {
+			return nil
+		}
+		return []string{markdownPrefixLines(inner, "> ", ">")}
+	case "ul", "ol":
+		if list := c.list(n); list != "" {
+			return []string{list}
+		}
+		return nil
+	case "table":
+		if table := c.table(n); table != "" {
+			return []string{table}
+		}
+		return nil
+	}
+	return c.blocks(n)
+}
+
+// blocks converts children of a node to Markdown blocks, consecutive inline
+// children are joined into a paragraph
+func (c *markdownConverter) blocks(n *html.Node) []string {
+	ret := make([]string, 0)
+	var inline strings.Builder
+	flush := func() {
+		if p := markdownParagraph(inline.String(), "  \n"); p != "" {
+			ret = append(ret, markdownEscapeLineStart(p))
+		}
+		inline.Reset()
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && htmlBlockTags[child.Data] {
+			flush()
+			ret = append(ret, c.block(child)...)
+			continue
+		}
+		inline.WriteString(c.inline(child))
+	}
+	flush()
+	return ret
+}
+
+// inline converts a node to inline Markdown, line breaks in the result are
+// hard breaks (<br>)
+func (c *markdownConverter) inline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return markdownEscaper.Replace(markdownSpacesRegexp.ReplaceAllString(n.Data, " "))
+	case html.ElementNode:
+	default:
+		return c.inlineChildren(n)
+	}
+	if markdownSkippedTags[n.Data] {
+		return ""
+	}
+	switch n.Data {
+	case "br":
+		return "\n"
+	case "strong", "b":
+		return markdownWrap(c.inlineChildren(n), "**")
+	case "em", "i":
+		return markdownWrap(c.inlineChildren(n), "*")
+	case "del", "s", "strike":
+		return markdownWrap(c.inlineChildren(n), "~~")
+	case "code", "kbd", "samp", "tt":
+		return markdownCodeSpan(htmlRawText(n))
+	case "a":
+		return c.link(n)
+	case "img":
+		return c.image(n)
+	}
+	text := c.inlineChildren(n)
+	if htmlBlockTags[n.Data] {
+		text = " " + text + " " // a block inside an inline element
+	}
+	return text
+}
+
+func (c *markdownConverter) inlineChildren(n *html.Node) string {
+	var buf strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		buf.WriteString(c.inline(child))
+	}
+	return buf.String()
+}
+
+func (c *markdownConverter) link(n *html.Node) string {
+	text := c.inlineChildren(n)
+	href := strings.TrimSpace(htmlGetAttr(n, "href"))
+	if href == "" || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+		return text
+	}
+	absURL, err := htmlResolveURL(c.base, href)
+	if err != nil {
+		return text
+	}
+	absURL = markdownURLEscaper.Replace(absURL)
+	if strings.TrimSpace(text) == "" {
+		return "<" + absURL + ">"
+	}
+	leading, inner, trailing := markdownSplitSpaces(strings.ReplaceAll(text, "\n", " "))
+	destination := absURL
+	if title := strings.TrimSpace(htmlGetAttr(n, "title")); title != "" {
+		destination += ` "` + strings.ReplaceAll(title, `"`, `\"`) + `"`
+	}
+	if !c.opts.ReferenceLinks {
+		return fmt.Sprintf("%v[%v](%v)%v", leading, inner, destination, trailing)
+	}
+	index, found := c.refIndex[destination]
+	if !found {
+		index = len(c.refs) + 1
+		c.refIndex[destination] = index
+		c.refs = append(c.refs, fmt.Sprintf("[%v]: %v", index, destination))
+	}
+	return fmt.Sprintf("%v[%v][%v]%v", leading, inner, index, trailing)
+}
+
+func (c *markdownConverter) image(n *html.Node) string {
+	if c.opts.DropImages {
+		return ""
+	}
+	src := htmlImgSrc(c.base, n)
+	if src == "" {
+		return ""
+	}
+	alt := markdownEscaper.Replace(markdownSpacesRegexp.ReplaceAllString(
+		strings.TrimSpace(htmlGetAttr(n, "alt")), " "))
+	return fmt.Sprintf("![%v](%v)", alt, markdownURLEscaper.Replace(src))
+}
+
+// list converts an ul or ol element to a Markdown list
+func (c *markdownConverter) list(n *html.Node) string {
+	number := 1
+	if start, err := strconv.Atoi(htmlGetAttr(n, "start")); err == nil {
+		number = start
+	}
+	items := make([]string, 0)
+	itemSeparator := "\n"
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode || li.Data != "li" {
+			continue
+		}
+		marker := "- "
+		if n.Data == "ol" {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+		separator := "\n" // tight list item
+		for child := li.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.ElementNode && child.Data == "p" {
+				separator = "\n\n"
+				itemSeparator = "\n\n" // loose list
+			}
+		}
+		content := strings.Join(c.blocks(li), separator)
+		indent := strings.Repeat(" ", len(marker))
+		items = append(items, marker+strings.TrimPrefix(
+			markdownPrefixLines(content, indent, ""), indent))
+	}
+	return strings.Join(items, itemSeparator)
+}
+
+// table converts a table element to a GFM table, the first row is the
+// header row
+func (c *markdownConverter) table(n *html.Node) string {
+	rows := make([][]string, 0)
+	var f func(*html.Node)
+	f = func(node *html.Node) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			switch child.Data {
+			case "thead", "tbody", "tfoot":
+				f(child)
+			case "tr":
+				row := make([]string, 0)
+				for cell := child.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.Type == html.ElementNode && (cell.Data == "td" || cell.Data == "th") {
+						text := markdownParagraph(c.inline(cell), " ")
+						row = append(row, strings.ReplaceAll(text, "|", `\|`))
+					}
+				}
+				rows = append(rows, row)
+			}
+		}
+	}
+	f(n)
+	nColumns := 0
+	for _, row := range rows {
+		nColumns = max(nColumns, len(row))
+	}
+	if nColumns == 0 {
+		return ""
+	}
+	lines := make([]string, 0, len(rows)+1)
+	for i, row := range rows {
+		for len(row) < nColumns {
+			row = append(row, "")
+		}
+		lines = append(lines, "| "+strings.Join(row, " | ")+" |")
+		if i == 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", nColumns))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// markdownCodeBlock converts a pre element to a fenced code block,
+// language is from the class "language-*" or "lang-*" of pre or code
+func markdownCodeBlock(pre *html.Node) string {
+	language := ""
+	for _, n := range []*html.Node{pre, pre.FirstChild} {
+		if n == nil || n.Type != html.ElementNode {
+			continue
+		}
+		for _, class := range strings.Fields(htmlGetAttr(n, "class")) {
+			for _, prefix := range []string{"language-", "lang-"} {
+				if strings.HasPrefix(class, prefix) && language == "" {
+					language = strings.TrimPrefix(class, prefix)
+				}
+			}
+		}
+	}
+	code := strings.TrimRight(htmlRawText(pre), "\n")
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fence + language + "\n" + code + "\n" + fence
+}
+
+// markdownCodeSpan returns an inline code span, the backtick fence is
+// longer than any backtick run in the code
+func markdownCodeSpan(code string) string {
+	code = markdownSpacesRegexp.ReplaceAllString(code, " ")
+	if strings.TrimSpace(code) == "" {
+		return code
+	}
+	fence := "`"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+		code = " " + code + " "
+	}
+	return fence + code + fence
+}
+
+// markdownWrap wraps the inline text by the emphasis marker, spaces around
+// the text are moved outside the markers
+func markdownWrap(text string, marker string) string {
+	leading, inner, trailing := markdownSplitSpaces(text)
+	if inner == "" {
+		return text
+	}
+	return leading + marker + inner + marker + trailing
+}
+
+func markdownSplitSpaces(text string) (leading string, inner string, trailing string) {
+	inner = strings.TrimLeft(text, " \n")
+	leading = text[:len(text)-len(inner)]
+	inner = strings.TrimRight(inner, " \n")
+	trailing = text[len(leading)+len(inner):]
+	return leading, inner, trailing
+}
+
+// markdownParagraph collapses spaces in the inline text, lines are joined
+// by the lineBreak
+func markdownParagraph(inline string, lineBreak string) string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(inline, "\n") {
+		line = strings.TrimSpace(markdownSpacesRegexp.ReplaceAllString(line, " "))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, lineBreak)
+}
+
+// markdownEscapeLineStart escapes chars at the start of lines of a
+// paragraph that would make the paragraph a heading, a list or a quote
+func markdownEscapeLineStart(paragraph string) string {
+	lines := strings.Split(paragraph, "\n")
+	for i, line := range lines {
+		if loc := markdownLineStartRegexp.FindStringSubmatchIndex(line); loc != nil {
+			markerEnd := loc[3]
+			lines[i] = line[:markerEnd-1] + `\` + line[markerEnd-1:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// markdownPrefixLines adds the prefix to non-empty lines of the text,
+// empty lines are replaced by emptyLine
+func markdownPrefixLines(text string, prefix string, emptyLine string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = emptyLine
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// htmlRawText returns the concatenated text nodes of the subtree,
+// spaces are kept
+func htmlRawText(node *html.Node) string {
+	var buf strings.Builder
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			buf.WriteString(n.Data)
+		}
+		if n.Type == html.ElementNode && n.Data == "br" {
+			buf.WriteString("\n")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+	f(node)
+	return buf.String()
+}
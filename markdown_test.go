@@ -0,0 +1,64 @@
+package textproc
+
+import (
+	"strings"
+	"testing"
+)
+
+const markdownTestHTML = `<html><head><title>x</title><base href="https://ex.com/vi/"></head><body>
+<h1>Tiêu  đề <em>chính</em></h1>
+<p>Đoạn <b>đậm </b>và <i>nghiêng</i>, <a href="tin/1.html" title="Tin 1">liên kết</a>, <code>a` + "`" + `b</code>, 2*3.<br>Dòng mới</p>
+<p>1. không phải danh sách</p>
+<ul><li>Một</li><li>Hai<ul><li>Hai một</li></ul></li></ul>
+<ol start="3"><li><p>Ba</p><p>đoạn 2</p></li><li>Bốn</li></ol>
+<blockquote><p>Trích dẫn</p><p>dòng 2</p></blockquote>
+<pre><code class="language-go">func main() {
+	fmt.Println("hi")
+}
+</code></pre>
+<table><thead><tr><th>Tên</th><th>Giá | VND</th></tr></thead><tbody><tr><td>A</td><td>1</td></tr><tr><td>B</td></tr></tbody></table>
+<figure><img src="a.jpg" alt="Ảnh A"><figcaption>Chú thích</figcaption></figure>
+<hr><p><a href="/x">x</a> <a href="/x">y</a> <a href="javascript:void(0)">js</a> <s>cũ</s></p>
+<script>var a = 1;</script>
+</body></html>`
+
+func TestHTMLToMarkdown(t *testing.T) {
+	root := HTMLParseToNode(markdownTestHTML)
+	r := HTMLToMarkdown("https://ex.com/a", root, MarkdownOptions{})
+	e := "# Tiêu đề *chính*\n\n" +
+		"Đoạn **đậm** và *nghiêng*, [liên kết](https://ex.com/vi/tin/1.html \"Tin 1\"), ``a`b``, 2\\*3.  \nDòng mới\n\n" +
+		"1\\. không phải danh sách\n\n" +
+		"- Một\n- Hai\n  - Hai một\n\n" +
+		"3. Ba\n\n   đoạn 2\n\n4. Bốn\n\n" +
+		"> Trích dẫn\n>\n> dòng 2\n\n" +
+		"```go\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n```\n\n" +
+		"| Tên | Giá \\| VND |\n| --- | --- |\n| A | 1 |\n| B |  |\n\n" +
+		"![Ảnh A](https://ex.com/vi/a.jpg)\n\nChú thích\n\n---\n\n" +
+		"[x](https://ex.com/x) [y](https://ex.com/x) js ~~cũ~~"
+	if r != e {
+		t.Errorf("error HTMLToMarkdown: real:\n%v\nexpected:\n%v", r, e)
+	}
+}
+
+func TestHTMLToMarkdownOptions(t *testing.T) {
+	root := HTMLParseToNode(markdownTestHTML)
+	r := HTMLToMarkdown("https://ex.com/a", root,
+		MarkdownOptions{DropImages: true, ReferenceLinks: true})
+	for _, e := range []string{
+		"[liên kết][1]",
+		"[x][2] [y][2]",
+		"\n\n[1]: https://ex.com/vi/tin/1.html \"Tin 1\"\n[2]: https://ex.com/x",
+	} {
+		if !strings.Contains(r, e) {
+			t.Errorf("error HTMLToMarkdown reference links: %q not in:\n%v", e, r)
+		}
+	}
+	if strings.Contains(r, "![") {
+		t.Errorf("error HTMLToMarkdown DropImages:\n%v", r)
+	}
+
+	codes, _ := HTMLXPath(HTMLParseToNode("<p>Gõ <code>ls -l</code></p>"), "//p")
+	if r := HTMLToMarkdown("", codes[0], MarkdownOptions{}); r != "Gõ `ls -l`" {
+		t.Errorf("error HTMLToMarkdown code span: %q", r)
+	}
+}
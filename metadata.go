@@ -0,0 +1,336 @@
+package textproc
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// PageMeta is the structured metadata of a HTML page,
+// result of HTMLGetMetadata
+type PageMeta struct {
+	Title         string
+	Description   string
+	URL           string // canonical URL
+	Image         string
+	SiteName      string
+	Type          string // example: "article", "NewsArticle"
+	Author        string
+	PublishedTime string // raw value, usually ISO 8601
+	ModifiedTime  string
+	Section       string
+	Language      string
+	Keywords      []string
+
+	// OpenGraph contains all "og:*" and "article:*" meta properties
+	OpenGraph map[string]string
+	// Twitter contains all "twitter:*" meta names
+	Twitter map[string]string
+	// JSONLD contains all parsed application/ld+json blocks
+	JSONLD []interface{}
+	// Microdata contains the first value of each itemprop (microdata)
+	// or property (RDFa) of elements that are not meta
+	Microdata map[string]string
+}
+
+// jsonLDArticleTypes is ordered by priority
+var jsonLDArticleTypes = []string{
+	"NewsArticle", "ReportageNewsArticle", "AnalysisNewsArticle", "Article",
+	"BlogPosting", "Report", "WebPage",
+}
+
+// HTMLGetMetadata merges OpenGraph, Twitter card, <meta name=description>,
+// canonical link, JSON-LD and microdata/RDFa into a PageMeta.
+// For each field, the first non-empty value in this order is used:
+// JSON-LD, OpenGraph, Twitter card, microdata/RDFa, plain HTML (<title>,
+// <meta name=...>). URL is an exception: <link rel=canonical> comes first.
+// URLs are resolved to absolute form in the same way as HTMLGetHREFs.
+func HTMLGetMetadata(baseURL string, node *html.Node) PageMeta {
+	ret := htmlGetMetadata(node)
+	pageURL, _ := url.Parse(baseURL)
+	ret.resolveURLs(htmlDocumentBase(pageURL, node))
+	return ret
+}
+
+// htmlGetMetadata is HTMLGetMetadata without resolving URLs
+func htmlGetMetadata(node *html.Node) PageMeta {
+	ret := PageMeta{
+		OpenGraph: make(map[string]string),
+		Twitter:   make(map[string]string),
+		JSONLD:    make([]interface{}, 0),
+		Microdata: make(map[string]string),
+	}
+	plainMetas := make(map[string]string) // <meta name=...>
+	var title, canonical, language string
+
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "html":
+				language = htmlGetAttr(n, "lang")
+			case "title":
+				if title == "" {
+					title = firstLine(HTMLGetText(n))
+				}
+			case "link":
+				if canonical == "" && hasToken(htmlGetAttr(n, "rel"), "canonical", false) {
+					canonical = htmlGetAttr(n, "href")
+				}
+			case "script":
+				if strings.EqualFold(strings.TrimSpace(htmlGetAttr(n, "type")),
+					"application/ld+json") && n.FirstChild != nil {
+					var v interface{}
+					if err := json.Unmarshal([]byte(n.FirstChild.Data), &v); err == nil {
+						ret.JSONLD = append(ret.JSONLD, v)
+					}
+				}
+			case "meta":
+				content := strings.TrimSpace(htmlGetAttr(n, "content"))
+				property := strings.ToLower(htmlGetAttr(n, "property"))
+				name := strings.ToLower(htmlGetAttr(n, "name"))
+				if content == "" {
+					break
+				}
+				for _, key := range []string{property, name} {
+					switch {
+					case strings.HasPrefix(key, "og:"), strings.HasPrefix(key, "article:"):
+						setIfEmpty(ret.OpenGraph, key, content)
+					case strings.HasPrefix(key, "twitter:"):
+						setIfEmpty(ret.Twitter, key, content)
+					}
+				}
+				if name != "" {
+					setIfEmpty(plainMetas, name, content)
+				}
+				if itemprop := htmlGetAttr(n, "itemprop"); itemprop != "" {
+					setIfEmpty(ret.Microdata, itemprop, content)
+				}
+			default:
+				for _, key := range []string{htmlGetAttr(n, "itemprop"), htmlGetAttr(n, "property")} {
+					if key != "" {
+						if v := microdataValue(n); v != "" {
+							setIfEmpty(ret.Microdata, stripRDFaPrefix(key), v)
+						}
+					}
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+	f(node)
+
+	ld := findJSONLDArticle(ret.JSONLD)
+	og, tw, md := ret.OpenGraph, ret.Twitter, ret.Microdata
+	ret.Title = NormalizeText(firstNonEmpty(jsonLDString(ld["headline"]),
+		jsonLDString(ld["name"]), og["og:title"], tw["twitter:title"],
+		md["headline"], md["name"], title))
+	ret.Description = NormalizeText(firstNonEmpty(jsonLDString(ld["description"]),
+		og["og:description"], tw["twitter:description"], md["description"],
+		plainMetas["description"]))
+	ret.URL = firstNonEmpty(canonical, jsonLDURL(ld["url"]),
+		jsonLDURL(ld["mainEntityOfPage"]), og["og:url"], tw["twitter:url"], md["url"])
+	ret.Image = firstNonEmpty(jsonLDURL(ld["image"]), og["og:image"],
+		og["og:image:url"], tw["twitter:image"], tw["twitter:image:src"], md["image"])
+	ret.SiteName = firstNonEmpty(jsonLDString(ld["publisher"]),
+		og["og:site_name"], tw["twitter:site"], plainMetas["application-name"])
+	ret.Type = firstNonEmpty(jsonLDString(ld["@type"]), og["og:type"])
+	ret.Author = NormalizeText(firstNonEmpty(jsonLDString(ld["author"]),
+		og["article:author"], tw["twitter:creator"], md["author"], plainMetas["author"]))
+	ret.PublishedTime = firstNonEmpty(jsonLDString(ld["datePublished"]),
+		og["article:published_time"], md["datePublished"], plainMetas["pubdate"],
+		plainMetas["date"])
+	ret.ModifiedTime = firstNonEmpty(jsonLDString(ld["dateModified"]),
+		og["article:modified_time"], og["og:updated_time"], md["dateModified"])
+	ret.Section = firstNonEmpty(jsonLDString(ld["articleSection"]),
+		og["article:section"], md["articleSection"])
+	ret.Language = firstNonEmpty(jsonLDString(ld["inLanguage"]), og["og:locale"],
+		md["inLanguage"], language)
+
+	keywords := jsonLDStrings(ld["keywords"])
+	if len(keywords) == 0 {
+		keywords = splitKeywords(firstNonEmpty(md["keywords"], plainMetas["keywords"],
+			plainMetas["news_keywords"]))
+	}
+	ret.Keywords = keywords
+
+	return ret
+}
+
+// resolveURLs resolves URL and Image against the base URL
+func (m *PageMeta) resolveURLs(base *url.URL) {
+	for _, field := range []*string{&m.URL, &m.Image} {
+		if *field == "" {
+			continue
+		}
+		if resolved, err := htmlResolveURL(base, *field); err == nil {
+			*field = resolved
+		}
+	}
+}
+
+func setIfEmpty(m map[string]string, key string, value string) {
+	if _, found := m[key]; !found {
+		m[key] = value
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// stripRDFaPrefix removes vocabulary prefix of a RDFa property,
+// example: "schema:headline" => "headline"
+func stripRDFaPrefix(property string) string {
+	if i := strings.LastIndexAny(property, ":/#"); i != -1 {
+		return property[i+1:]
+	}
+	return property
+}
+
+// microdataValue returns the value of an element that has itemprop
+func microdataValue(n *html.Node) string {
+	if v := htmlGetAttr(n, "content"); v != "" {
+		return v
+	}
+	switch n.Data {
+	case "a", "link", "area":
+		return htmlGetAttr(n, "href")
+	case "img", "audio", "video", "source", "iframe", "embed":
+		return htmlGetAttr(n, "src")
+	case "time":
+		if v := htmlGetAttr(n, "datetime"); v != "" {
+			return v
+		}
+	case "data", "meter":
+		return htmlGetAttr(n, "value")
+	}
+	if htmlHasAttr(n, "itemscope") {
+		// nested item, example: author is a Person, use its name
+		names, _ := HTMLXPath(n, `.//*[@itemprop='name']`)
+		if len(names) > 0 {
+			return microdataValue(names[0])
+		}
+	}
+	return firstLine(HTMLGetText(n))
+}
+
+func splitKeywords(s string) []string {
+	ret := make([]string, 0)
+	for _, k := range strings.Split(s, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			ret = append(ret, k)
+		}
+	}
+	return ret
+}
+
+// findJSONLDArticle returns the JSON-LD object that has the highest
+// priority type in jsonLDArticleTypes, or an empty map if no object has
+// these types (an Organization or WebSite object does not describe the page)
+func findJSONLDArticle(blocks []interface{}) map[string]interface{} {
+	objects := make([]map[string]interface{}, 0)
+	var collect func(v interface{})
+	collect = func(v interface{}) {
+		switch v := v.(type) {
+		case []interface{}:
+			for _, e := range v {
+				collect(e)
+			}
+		case map[string]interface{}:
+			objects = append(objects, v)
+			if graph, found := v["@graph"]; found {
+				collect(graph)
+			}
+		}
+	}
+	for _, block := range blocks {
+		collect(block)
+	}
+	for _, typ := range jsonLDArticleTypes {
+		for _, obj := range objects {
+			for _, objType := range jsonLDStrings(obj["@type"]) {
+				if objType == typ {
+					return obj
+				}
+			}
+		}
+	}
+	return map[string]interface{}{}
+}
+
+// jsonLDURL returns an URL from a JSON-LD value: a string, the first
+// element of an array, or "url", "contentUrl" or "@id" of an object
+// (example: an ImageObject), the "name" of an object is not an URL
+func jsonLDURL(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []interface{}:
+		for _, e := range v {
+			if s := jsonLDURL(e); s != "" {
+				return s
+			}
+		}
+	case map[string]interface{}:
+		for _, key := range []string{"url", "contentUrl", "@id"} {
+			if s := jsonLDURL(v[key]); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// jsonLDString returns a string from a JSON-LD value: a string, the first
+// element of an array, or "name", "url" or "@id" of an object (example: an
+// author or a publisher), jsonLDURL is used for URL fields
+func jsonLDString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		for _, e := range v {
+			if s := jsonLDString(e); s != "" {
+				return s
+			}
+		}
+	case map[string]interface{}:
+		for _, key := range []string{"name", "url", "@id"} {
+			if s := jsonLDString(v[key]); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// jsonLDStrings returns a list of strings from a JSON-LD value, a string
+// value is split by commas
+func jsonLDStrings(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return splitKeywords(v)
+	case []interface{}:
+		ret := make([]string, 0, len(v))
+		for _, e := range v {
+			if s := jsonLDString(e); s != "" {
+				ret = append(ret, s)
+			}
+		}
+		return ret
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package textproc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHTMLGetMetadata(t *testing.T) {
+	page := `<html lang="vi"><head>
+<title>Tồn kho tại Mỹ giảm - NDH</title>
+<meta name="description" content="Plain description">
+<meta name="keywords" content="giá dầu, giá vàng">
+<meta property="og:title" content="Og title">
+<meta property="og:description" content="Og description">
+<meta property="og:image" content="/2020/08/06/download2.jpg">
+<meta property="og:site_name" content="NDH">
+<meta property="og:type" content="article">
+<meta property="article:section" content="Năng lượng">
+<meta name="twitter:card" content="summary_large_image">
+<meta name="twitter:title" content="Twitter title">
+<link rel="canonical" href="/nang-luong/ton-kho-1273458.html#top">
+<script type="application/ld+json">
+{"@context": "https://schema.org", "@graph": [
+  {"@type": "WebSite", "name": "NDH", "url": "https://ndh.vn"},
+  {"@type": "NewsArticle", "headline": "Tồn kho tại Mỹ giảm, giá dầu lên đỉnh 5 tháng",
+   "datePublished": "2020-08-06T07:10:00+07:00",
+   "author": [{"@type": "Person", "name": "Như Tâm"}],
+   "publisher": {"@type": "Organization", "name": "Nhịp sống kinh tế"}}
+]}
+</script>
+<script type="application/ld+json">{invalid json</script>
+</head><body>
+<div itemscope itemtype="https://schema.org/NewsArticle">
+  <span itemprop="dateModified" content="2020-08-06T08:00:00+07:00"></span>
+  <img itemprop="image" src="/microdata.jpg">
+</div>
+</body></html>`
+	meta := HTMLGetMetadata("https://ndh.vn/news", HTMLParseToNode(page))
+	expected := PageMeta{
+		Title:         "Tồn kho tại Mỹ giảm, giá dầu lên đỉnh 5 tháng",
+		Description:   "Og description",
+		URL:           "https://ndh.vn/nang-luong/ton-kho-1273458.html",
+		Image:         "https://ndh.vn/2020/08/06/download2.jpg",
+		SiteName:      "Nhịp sống kinh tế",
+		Type:          "NewsArticle",
+		Author:        "Như Tâm",
+		PublishedTime: "2020-08-06T07:10:00+07:00",
+		ModifiedTime:  "2020-08-06T08:00:00+07:00",
+		Section:       "Năng lượng",
+		Language:      "vi",
+		Keywords:      []string{"giá dầu", "giá vàng"},
+	}
+	if len(meta.JSONLD) != 1 || meta.Twitter["twitter:card"] != "summary_large_image" ||
+		meta.OpenGraph["og:type"] != "article" || meta.Microdata["image"] != "/microdata.jpg" {
+		t.Errorf("error HTMLGetMetadata raw values: %+v", meta)
+	}
+	meta.OpenGraph, meta.Twitter, meta.JSONLD, meta.Microdata = nil, nil, nil, nil
+	if !reflect.DeepEqual(meta, expected) {
+		t.Errorf("error HTMLGetMetadata:\nreal:     %+v\nexpected: %+v", meta, expected)
+	}
+
+	orgPage := HTMLParseToNode(`<html><head><title>Tin nóng - NDH</title>
+<meta property="og:title" content="Tin nóng">
+<meta property="og:url" content="https://ndh.vn/tin-nong-1.html">
+<script type="application/ld+json">{"@context": "https://schema.org",
+"@type": "Organization", "name": "NDH", "url": "https://ndh.vn",
+"logo": "https://ndh.vn/logo.png"}</script></head><body></body></html>`)
+	meta = HTMLGetMetadata("https://ndh.vn/", orgPage)
+	if meta.Title != "Tin nóng" || meta.URL != "https://ndh.vn/tin-nong-1.html" ||
+		meta.Type != "" || len(meta.JSONLD) != 1 {
+		t.Errorf("error HTMLGetMetadata Organization JSON-LD: %+v", meta)
+	}
+
+	imageObjectPage := HTMLParseToNode(`<html><head><script type="application/ld+json">
+{"@type": "NewsArticle", "headline": "Tin", "mainEntityOfPage": {"@type": "WebPage",
+"@id": "https://ndh.vn/tin-1.html"}, "image": {"@type": "ImageObject", "name": "caption",
+"url": "https://ndh.vn/a.jpg"}}</script></head><body></body></html>`)
+	meta = HTMLGetMetadata("https://ndh.vn/", imageObjectPage)
+	if meta.Image != "https://ndh.vn/a.jpg" || meta.URL != "https://ndh.vn/tin-1.html" {
+		t.Errorf("error HTMLGetMetadata ImageObject: %+v", meta)
+	}
+
+	basePage := HTMLParseToNode(`<html><head><base href="https://cdn.b.com/">
+<meta property="og:image" content="img/a.jpg"><link rel="canonical" href="p.html">
+</head><body><a href="p.html">p</a></body></html>`)
+	meta = HTMLGetMetadata("https://a.com/x/p.html", basePage)
+	if e := "https://cdn.b.com/p.html"; meta.URL != e {
+		t.Errorf("error HTMLGetMetadata base URL: real: %v, expected: %v", meta.URL, e)
+	}
+	if e := "https://cdn.b.com/img/a.jpg"; meta.Image != e {
+		t.Errorf("error HTMLGetMetadata base Image: real: %v, expected: %v", meta.Image, e)
+	}
+	if hrefs := HTMLGetHREFs("https://a.com/x/p.html", basePage); len(hrefs) != 1 ||
+		hrefs[0] != meta.URL {
+		t.Errorf("error HTMLGetMetadata URL differs from HTMLGetHREFs: %v, %v", meta.URL, hrefs)
+	}
+
+	meta = HTMLGetMetadata("", HTMLParseToNode(`<title>Only title</title>`))
+	if meta.Title != "Only title" || meta.URL != "" || len(meta.Keywords) != 0 {
+		t.Errorf("error HTMLGetMetadata minimal page: %+v", meta)
+	}
+}
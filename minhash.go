@@ -0,0 +1,263 @@
+package textproc
+
+import (
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"sort"
+	"sync"
+)
+
+// minHashSeed makes MinHash signatures from different processes comparable,
+// changing it invalidates all saved signatures
+const minHashSeed uint64 = 0x6d696e68617368 // "minhash"
+
+// splitMix64 is a fast deterministic pseudo random generator,
+// it returns the next state and a random number
+func splitMix64(state uint64) (uint64, uint64) {
+	state += 0x9e3779b97f4a7c15
+	return state, mix64(state)
+}
+
+// mix64 is the finalizer of splitMix64, it scrambles all bits of the input
+func mix64(z uint64) uint64 {
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// MinHashSignature returns numPerm min hashes of the n-gram set
+// (result of WordsToNGrams or TextToNGrams, counts are ignored).
+// The fraction of equal positions in 2 signatures estimates the Jaccard
+// similarity of the 2 sets, see EstimateJaccard.
+func MinHashSignature(ngrams map[string]int, numPerm int) []uint64 {
+	if numPerm <= 0 {
+		return []uint64{}
+	}
+	seeds := make([]uint64, numPerm)
+	state := minHashSeed
+	for i := range seeds {
+		state, seeds[i] = splitMix64(state)
+	}
+	sig := make([]uint64, numPerm)
+	for i := range sig {
+		sig[i] = math.MaxUint64
+	}
+	for ngram := range ngrams {
+		h := uint64(HashTextToInt(ngram))
+		for i, seed := range seeds {
+			if v := mix64(h ^ seed); v < sig[i] {
+				sig[i] = v
+			}
+		}
+	}
+	return sig
+}
+
+// EstimateJaccard returns fraction of equal positions in 2 MinHash
+// signatures, returns 0 if the signatures have different lengths
+func EstimateJaccard(sig1 []uint64, sig2 []uint64) float64 {
+	if len(sig1) != len(sig2) || len(sig1) == 0 {
+		return 0
+	}
+	nEquals := 0
+	for i := range sig1 {
+		if sig1[i] == sig2[i] {
+			nEquals++
+		}
+	}
+	return float64(nEquals) / float64(len(sig1))
+}
+
+// LSHCandidate is a result of LSHIndex_Query
+type LSHCandidate struct {
+	ID      string
+	Jaccard float64 // estimated by EstimateJaccard
+}
+
+// LSHIndex (locality-sensitive hashing) finds stored MinHash signatures
+// that are likely similar to a query signature. A signature is split into
+// bands of rows, 2 signatures are candidates if they have at least one
+// equal band. More rows per band make fewer false positives, more bands
+// make fewer false negatives.
+// LSHIndex is safe for concurrent use.
+type LSHIndex struct {
+	bands int
+	rows  int
+	mu    sync.RWMutex
+	// tables[i] maps hash of band i to ids
+	tables     []map[uint64][]string
+	signatures map[string][]uint64
+}
+
+// NewLSHIndex returns an empty index for signatures of length bands*rows
+func NewLSHIndex(bands int, rows int) *LSHIndex {
+	if bands < 1 {
+		bands = 1
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	idx := &LSHIndex{
+		bands:      bands,
+		rows:       rows,
+		tables:     make([]map[uint64][]string, bands),
+		signatures: make(map[string][]uint64),
+	}
+	for i := range idx.tables {
+		idx.tables[i] = make(map[uint64][]string)
+	}
+	return idx
+}
+
+// Bands returns number of bands in a signature
+func (idx *LSHIndex) Bands() int { return idx.bands }
+
+// Rows returns number of rows in a band
+func (idx *LSHIndex) Rows() int { return idx.rows }
+
+// Len returns number of signatures in the index
+func (idx *LSHIndex) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.signatures)
+}
+
+func (idx *LSHIndex) checkSignature(sig []uint64) error {
+	if len(sig) != idx.bands*idx.rows {
+		return fmt.Errorf("error signature length: real: %v, expected: %v",
+			len(sig), idx.bands*idx.rows)
+	}
+	return nil
+}
+
+// bandHash returns the bucket key of the band i of the signature
+func (idx *LSHIndex) bandHash(sig []uint64, i int) uint64 {
+	h := fnv.New64a()
+	buf := make([]byte, 8)
+	for _, v := range sig[i*idx.rows : (i+1)*idx.rows] {
+		binary.LittleEndian.PutUint64(buf, v)
+		_, _ = h.Write(buf)
+	}
+	return h.Sum64()
+}
+
+// Insert stores a signature, an existing signature with the same id
+// will be replaced
+func (idx *LSHIndex) Insert(id string, sig []uint64) error {
+	if err := idx.checkSignature(sig); err != nil {
+		return err
+	}
+	stored := make([]uint64, len(sig))
+	copy(stored, sig)
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if _, found := idx.signatures[id]; found {
+		idx.remove(id)
+	}
+	idx.signatures[id] = stored
+	for i := range idx.tables {
+		key := idx.bandHash(stored, i)
+		idx.tables[i][key] = append(idx.tables[i][key], id)
+	}
+	return nil
+}
+
+// Remove deletes the signature with the id, does nothing if not found
+func (idx *LSHIndex) Remove(id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(id)
+}
+
+// remove must be called while holding the lock
+func (idx *LSHIndex) remove(id string) {
+	sig, found := idx.signatures[id]
+	if !found {
+		return
+	}
+	delete(idx.signatures, id)
+	for i := range idx.tables {
+		key := idx.bandHash(sig, i)
+		bucket := idx.tables[i][key]
+		for j, bucketID := range bucket {
+			if bucketID == id {
+				bucket = append(bucket[:j], bucket[j+1:]...)
+				break
+			}
+		}
+		if len(bucket) == 0 {
+			delete(idx.tables[i], key)
+		} else {
+			idx.tables[i][key] = bucket
+		}
+	}
+}
+
+// Query returns ids of stored signatures that share at least one band
+// with the input signature, sorted by estimated Jaccard (descending) then id
+func (idx *LSHIndex) Query(sig []uint64) ([]LSHCandidate, error) {
+	if err := idx.checkSignature(sig); err != nil {
+		return nil, err
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	checked := make(map[string]bool)
+	ret := make([]LSHCandidate, 0)
+	for i := range idx.tables {
+		for _, id := range idx.tables[i][idx.bandHash(sig, i)] {
+			if checked[id] {
+				continue
+			}
+			checked[id] = true
+			ret = append(ret, LSHCandidate{
+				ID: id, Jaccard: EstimateJaccard(sig, idx.signatures[id])})
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Jaccard != ret[j].Jaccard {
+			return ret[i].Jaccard > ret[j].Jaccard
+		}
+		return ret[i].ID < ret[j].ID
+	})
+	return ret, nil
+}
+
+// lshIndexData is the saved form of LSHIndex
+type lshIndexData struct {
+	Bands      int
+	Rows       int
+	Signatures map[string][]uint64
+}
+
+// Save writes the index to the writer, the index can be read by LoadLSHIndex
+func (idx *LSHIndex) Save(w io.Writer) error {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	data := lshIndexData{Bands: idx.bands, Rows: idx.rows, Signatures: idx.signatures}
+	if err := gob.NewEncoder(w).Encode(data); err != nil {
+		return fmt.Errorf("error gob Encode: %v", err)
+	}
+	return nil
+}
+
+// LoadLSHIndex reads an index that was written by LSHIndex_Save
+func LoadLSHIndex(r io.Reader) (*LSHIndex, error) {
+	var data lshIndexData
+	if err := gob.NewDecoder(r).Decode(&data); err != nil {
+		return nil, fmt.Errorf("error gob Decode: %v", err)
+	}
+	if data.Bands < 1 || data.Rows < 1 {
+		return nil, fmt.Errorf("error invalid bands %v or rows %v", data.Bands, data.Rows)
+	}
+	idx := NewLSHIndex(data.Bands, data.Rows)
+	for id, sig := range data.Signatures {
+		if err := idx.Insert(id, sig); err != nil {
+			return nil, fmt.Errorf("error Insert %v: %v", id, err)
+		}
+	}
+	return idx, nil
+}
@@ -0,0 +1,80 @@
+package textproc
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestMinHashSignature(t *testing.T) {
+	set1 := map[string]int{}
+	set2 := map[string]int{}
+	for i := 0; i < 100; i++ {
+		word := GenRandomWord(8, 12, AlphaNumericEnList)
+		set1[word] = 1
+		if i < 60 {
+			set2[word] = 1
+		}
+	}
+	for i := 0; i < 40; i++ {
+		set2[GenRandomWord(8, 12, AlphaNumericEnList)] = 1
+	}
+	// real Jaccard = 60 / 140
+	sig1, sig2 := MinHashSignature(set1, 256), MinHashSignature(set2, 256)
+	if j := EstimateJaccard(sig1, sig2); math.Abs(j-60.0/140) > 0.1 {
+		t.Errorf("error EstimateJaccard: real: %v, expected: %v", j, 60.0/140)
+	}
+	if EstimateJaccard(sig1, MinHashSignature(set1, 256)) != 1 {
+		t.Errorf("error MinHashSignature is not deterministic")
+	}
+	// signatures with more permutations extend the shorter ones
+	sig3 := MinHashSignature(set1, 16)
+	for i := range sig3 {
+		if sig3[i] != sig1[i] {
+			t.Fatalf("error MinHashSignature prefix")
+		}
+	}
+}
+
+func TestLSHIndex(t *testing.T) {
+	idx := NewLSHIndex(16, 4)
+	doc1 := TextToNGrams(paragraphs[1], 2)
+	doc2 := TextToNGrams(paragraphs[1]+" and he did it", 2)
+	for i, para := range paragraphs {
+		if err := idx.Insert(string(rune('a'+i)), MinHashSignature(TextToNGrams(para, 2), 64)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := idx.Insert("short", []uint64{1, 2}); err == nil {
+		t.Errorf("expect error signature length")
+	}
+
+	candidates, err := idx.Query(MinHashSignature(doc2, 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 1 || candidates[0].ID != "b" || candidates[0].Jaccard < 0.7 {
+		t.Errorf("error LSHIndex Query: %+v", candidates)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := idx.Save(buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadLSHIndex(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Len() != len(paragraphs) || loaded.Bands() != 16 || loaded.Rows() != 4 {
+		t.Errorf("error LoadLSHIndex: len %v", loaded.Len())
+	}
+	candidates, _ = loaded.Query(MinHashSignature(doc1, 64))
+	if len(candidates) != 1 || candidates[0].ID != "b" || candidates[0].Jaccard != 1 {
+		t.Errorf("error loaded LSHIndex Query: %+v", candidates)
+	}
+
+	loaded.Remove("b")
+	if candidates, _ = loaded.Query(MinHashSignature(doc1, 64)); len(candidates) != 0 {
+		t.Errorf("error LSHIndex Remove: %+v", candidates)
+	}
+}
@@ -0,0 +1,283 @@
+package textproc
+
+import (
+	"maps"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Sanitizer is an allowlist policy to remove dangerous markup from HTML.
+// Elements that are not allowed are unwrapped (their children are kept and
+// sanitized), except DropElements which are removed with their content.
+// Attributes that are not allowed, event handlers (on*) and URLs that have
+// a not allowed scheme (javascript:, data:, ...) are removed.
+// Comments are removed. Elements html, head and body are kept without
+// attributes so a sanitized document is still a valid document.
+// A Sanitizer can be used concurrently after it is configured.
+type Sanitizer struct {
+	// DropElements are removed with their content, default: script, style,
+	// iframe, object, form controls, ...
+	DropElements map[string]bool
+	// AllowRelativeURLs allows URLs that do not have a scheme
+	AllowRelativeURLs bool
+	// RequireNoFollow sets rel="nofollow noopener" on links (a, area)
+	RequireNoFollow bool
+
+	elements    map[string]map[string]bool // allowed element to its allowed attrs
+	globalAttrs map[string]bool
+	urlSchemes  map[string]bool
+}
+
+// DefaultSanitizerDropElements is the default DropElements of a Sanitizer
+var DefaultSanitizerDropElements = toMapStrings(
+	"script", "style", "noscript", "template", "iframe", "frame", "frameset",
+	"object", "embed", "applet", "title", "meta", "link", "base", "svg",
+	"math", "button", "input", "select", "option", "textarea")
+
+// sanitizerURLAttrs are attributes that contain URLs
+var sanitizerURLAttrs = toMapStrings(
+	"href", "src", "cite", "action", "formaction", "poster", "background",
+	"longdesc", "usemap", "data", "manifest", "srcset")
+
+// NewSanitizer returns a policy that does not allow any element (result is
+// text only), callers use AllowElements, AllowAttrs, AllowURLSchemes to
+// configure the policy
+func NewSanitizer() *Sanitizer {
+	return &Sanitizer{
+		DropElements:      maps.Clone(DefaultSanitizerDropElements),
+		AllowRelativeURLs: true,
+		elements:          make(map[string]map[string]bool),
+		globalAttrs:       make(map[string]bool),
+		urlSchemes:        make(map[string]bool),
+	}
+}
+
+// NewStrictSanitizer returns a policy that removes all markup, only text is kept
+func NewStrictSanitizer() *Sanitizer {
+	return NewSanitizer()
+}
+
+// NewUGCSanitizer returns a policy for user generated content (comments,
+// forum posts): text formatting, lists, quotes, code and links to http,
+// https and mailto URLs, links are nofollow
+func NewUGCSanitizer() *Sanitizer {
+	s := NewSanitizer()
+	s.AllowElements("p", "br", "b", "strong", "i", "em", "u", "s", "del",
+		"ins", "sub", "sup", "small", "mark", "code", "pre", "kbd",
+		"blockquote", "q", "ul", "ol", "li", "a", "abbr", "span")
+	s.AllowAttrs("a", "href", "title")
+	s.AllowAttrs("blockquote", "cite")
+	s.AllowAttrs("q", "cite")
+	s.AllowAttrs("ol", "start")
+	s.AllowAttrs("abbr", "title")
+	s.AllowURLSchemes("http", "https", "mailto")
+	s.RequireNoFollow = true
+	return s
+}
+
+// NewArticleSanitizer returns a policy to re-publish article content: the
+// UGC policy plus headings, images, figures, tables and sections
+func NewArticleSanitizer() *Sanitizer {
+	s := NewUGCSanitizer()
+	s.AllowElements("h1", "h2", "h3", "h4", "h5", "h6", "hr", "div",
+		"section", "article", "header", "footer", "aside", "figure",
+		"figcaption", "picture", "source", "img", "table", "caption",
+		"thead", "tbody", "tfoot", "tr", "th", "td", "dl", "dt", "dd", "time",
+		"cite")
+	s.AllowAttrs("img", "src", "srcset", "sizes", "alt", "width", "height")
+	s.AllowAttrs("source", "srcset", "sizes", "type", "media")
+	s.AllowAttrs("th", "colspan", "rowspan", "scope")
+	s.AllowAttrs("td", "colspan", "rowspan")
+	s.AllowAttrs("time", "datetime")
+	s.AllowGlobalAttrs("title", "lang", "dir")
+	return s
+}
+
+// AllowElements allows the elements (without attributes)
+func (s *Sanitizer) AllowElements(elements ...string) {
+	for _, e := range elements {
+		e = strings.ToLower(e)
+		if s.elements[e] == nil {
+			s.elements[e] = make(map[string]bool)
+		}
+	}
+}
+
+// AllowAttrs allows the element and the attributes on it
+func (s *Sanitizer) AllowAttrs(element string, attrs ...string) {
+	s.AllowElements(element)
+	for _, attr := range attrs {
+		s.elements[strings.ToLower(element)][strings.ToLower(attr)] = true
+	}
+}
+
+// AllowGlobalAttrs allows the attributes on all allowed elements
+func (s *Sanitizer) AllowGlobalAttrs(attrs ...string) {
+	for _, attr := range attrs {
+		s.globalAttrs[strings.ToLower(attr)] = true
+	}
+}
+
+// AllowURLSchemes allows URLs that have the schemes, example: "https"
+func (s *Sanitizer) AllowURLSchemes(schemes ...string) {
+	for _, scheme := range schemes {
+		s.urlSchemes[strings.ToLower(scheme)] = true
+	}
+}
+
+// Sanitize modifies the tree in place, the node itself is never removed
+// (its attributes are removed if it is not allowed)
+func (s *Sanitizer) Sanitize(node *html.Node) {
+	if node.Type == html.ElementNode {
+		if _, allowed := s.elements[node.Data]; allowed && node.Namespace == "" {
+			s.sanitizeAttrs(node)
+		} else {
+			node.Attr = nil
+		}
+	}
+	s.sanitizeChildren(node)
+}
+
+// SanitizeCopy returns a sanitized deep copy of the tree,
+// the input tree is not modified
+func (s *Sanitizer) SanitizeCopy(node *html.Node) *html.Node {
+	ret := htmlCloneNode(node)
+	s.Sanitize(ret)
+	return ret
+}
+
+// SanitizeHTML sanitizes a HTML fragment, example:
+// `<p onclick="x()">Hi <script>alert(1)</script></p>` => `<p>Hi </p>` (UGC)
+func (s *Sanitizer) SanitizeHTML(fragment string) string {
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), body)
+	if err != nil {
+		return ""
+	}
+	for _, n := range nodes {
+		body.AppendChild(n)
+	}
+	s.Sanitize(body)
+	var buf strings.Builder
+	for c := body.FirstChild; c != nil; c = c.NextSibling {
+		buf.WriteString(HTMLRender(c))
+	}
+	return buf.String()
+}
+
+func (s *Sanitizer) sanitizeChildren(parent *html.Node) {
+	for c := parent.FirstChild; c != nil; {
+		next := c.NextSibling
+		switch c.Type {
+		case html.CommentNode:
+			parent.RemoveChild(c)
+		case html.ElementNode:
+			_, allowed := s.elements[c.Data]
+			switch {
+			case c.Data == "html" || c.Data == "head" || c.Data == "body":
+				c.Attr = nil
+				s.sanitizeChildren(c)
+			case s.DropElements[c.Data] || c.Namespace != "":
+				parent.RemoveChild(c)
+			case allowed:
+				s.sanitizeAttrs(c)
+				s.sanitizeChildren(c)
+			default: // unwrap, the children are sanitized in next iterations
+				if c.FirstChild != nil {
+					next = c.FirstChild
+				}
+				for child := c.FirstChild; child != nil; child = c.FirstChild {
+					c.RemoveChild(child)
+					parent.InsertBefore(child, c)
+				}
+				parent.RemoveChild(c)
+			}
+		}
+		c = next
+	}
+}
+
+func (s *Sanitizer) sanitizeAttrs(n *html.Node) {
+	allowedAttrs := s.elements[n.Data]
+	attrs := make([]html.Attribute, 0, len(n.Attr))
+	for _, attr := range n.Attr {
+		key := strings.ToLower(attr.Key)
+		if attr.Namespace != "" || strings.HasPrefix(key, "on") ||
+			!allowedAttrs[key] && !s.globalAttrs[key] {
+			continue
+		}
+		if sanitizerURLAttrs[key] && !s.isAllowedURLAttr(key, attr.Val) {
+			continue
+		}
+		attrs = append(attrs, attr)
+	}
+	n.Attr = attrs
+
+	if s.RequireNoFollow && (n.Data == "a" || n.Data == "area") && htmlHasAttr(n, "href") {
+		rels := strings.Fields(strings.ToLower(htmlGetAttr(n, "rel")))
+		for _, required := range []string{"nofollow", "noopener"} {
+			if !hasToken(strings.Join(rels, " "), required, false) {
+				rels = append(rels, required)
+			}
+		}
+		htmlSetAttr(n, "rel", strings.Join(rels, " "))
+	}
+}
+
+func (s *Sanitizer) isAllowedURLAttr(key string, value string) bool {
+	if key != "srcset" {
+		return s.isAllowedURL(value)
+	}
+	for _, c := range parseSrcset(value) {
+		if !s.isAllowedURL(c.URL) {
+			return false
+		}
+	}
+	return true
+}
+
+// isAllowedURL checks the URL scheme, browsers ignore spaces and control
+// chars in a scheme (example: "java\tscript:") so they are removed first
+func (s *Sanitizer) isAllowedURL(rawURL string) bool {
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, rawURL)
+	colon := strings.Index(cleaned, ":")
+	if colon == -1 || strings.ContainsAny(cleaned[:colon], "/?#") {
+		return s.AllowRelativeURLs
+	}
+	return s.urlSchemes[strings.ToLower(cleaned[:colon])]
+}
+
+// htmlSetAttr sets value of the attribute key of the node,
+// the attribute is added if the node does not have it
+func htmlSetAttr(n *html.Node, key string, value string) {
+	for i, attr := range n.Attr {
+		if attr.Key == key && attr.Namespace == "" {
+			n.Attr[i].Val = value
+			return
+		}
+	}
+	n.Attr = append(n.Attr, html.Attribute{Key: key, Val: value})
+}
+
+// htmlCloneNode returns a deep copy of the subtree,
+// the copy does not have parent and siblings
+func htmlCloneNode(n *html.Node) *html.Node {
+	ret := &html.Node{
+		Type:      n.Type,
+		DataAtom:  n.DataAtom,
+		Data:      n.Data,
+		Namespace: n.Namespace,
+		Attr:      append([]html.Attribute(nil), n.Attr...),
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		ret.AppendChild(htmlCloneNode(c))
+	}
+	return ret
+}
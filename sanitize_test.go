@@ -0,0 +1,81 @@
+package textproc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizerUGC(t *testing.T) {
+	s := NewUGCSanitizer()
+	for i, c := range []struct{ in, expected string }{
+		{`<p onclick="x()">Xin <b>chào</b><script>alert(1)</script></p>`,
+			`<p>Xin <b>chào</b></p>`},
+		{`<a href="javascript:alert(1)">a</a> <a href=" JaVa&#09;script:alert(1)">b</a>`,
+			`<a>a</a> <a>b</a>`},
+		{`<a href="/tin/1.html" rel="ugc" target="_blank">tin</a>`,
+			`<a href="/tin/1.html" rel="nofollow noopener">tin</a>`},
+		{`<div class="x"><h1>Tiêu đề</h1><img src="data:image/png;base64,AAA"></div>`,
+			`Tiêu đề`},
+		{`<a href="mailto:a@b.vn" title="t">mail</a><!-- comment -->`,
+			`<a href="mailto:a@b.vn" title="t" rel="nofollow noopener">mail</a>`},
+		{`<iframe src="https://evil.com"></iframe><svg><script>x</script></svg>ok`, `ok`},
+	} {
+		if r := s.SanitizeHTML(c.in); r != c.expected {
+			t.Errorf("error SanitizeHTML %v: real: %v, expected: %v", i, r, c.expected)
+		}
+	}
+	if r := NewStrictSanitizer().SanitizeHTML(`<p>a <i>b</i></p><p>c</p>`); r != "a bc" {
+		t.Errorf("error strict SanitizeHTML: %v", r)
+	}
+}
+
+func TestSanitizerArticle(t *testing.T) {
+	s := NewArticleSanitizer()
+	r := s.SanitizeHTML(`<figure style="x"><img src="https://a.vn/1.jpg" ` +
+		`srcset="https://a.vn/2.jpg 2x, javascript:x 3x" alt="Ảnh" onerror="x()">` +
+		`<figcaption lang="vi">Chú thích</figcaption></figure>` +
+		`<table><tr><td colspan="2" bgcolor="red">1</td></tr></table>`)
+	e := `<figure><img src="https://a.vn/1.jpg" alt="Ảnh"/>` +
+		`<figcaption lang="vi">Chú thích</figcaption></figure>` +
+		`<table><tbody><tr><td colspan="2">1</td></tr></tbody></table>`
+	if r != e {
+		t.Errorf("error article SanitizeHTML: real: %v, expected: %v", r, e)
+	}
+
+	s.AllowRelativeURLs = false
+	if r := s.SanitizeHTML(`<a href="/x">x</a>`); r != `<a>x</a>` {
+		t.Errorf("error SanitizeHTML AllowRelativeURLs: %v", r)
+	}
+}
+
+func TestSanitizerCopy(t *testing.T) {
+	root := HTMLParseToNode(`<html><head><title>T</title></head>` +
+		`<body class="b"><p>Hi<script>x()</script></p></body></html>`)
+	before := HTMLRender(root)
+	cleaned := NewUGCSanitizer().SanitizeCopy(root)
+	if HTMLRender(root) != before {
+		t.Errorf("error SanitizeCopy modified the input")
+	}
+	r := HTMLRender(cleaned)
+	if e := `<html><head></head><body><p>Hi</p></body></html>`; r != e {
+		t.Errorf("error SanitizeCopy: real: %v, expected: %v", r, e)
+	}
+	NewUGCSanitizer().Sanitize(root)
+	if HTMLRender(root) != r || strings.Contains(HTMLRender(root), "script") {
+		t.Errorf("error Sanitize in place: %v", HTMLRender(root))
+	}
+}
+
+func TestSanitizerIndependentPolicies(t *testing.T) {
+	ugc := NewUGCSanitizer()
+	ugc.DropElements["b"] = true
+	if r := ugc.SanitizeHTML(`<b>bold</b>`); r != "" {
+		t.Errorf("error DropElements: real: %v, expected: %v", r, "")
+	}
+	if r, e := NewArticleSanitizer().SanitizeHTML(`<b>bold</b>`), `<b>bold</b>`; r != e {
+		t.Errorf("error other policy changed: real: %v, expected: %v", r, e)
+	}
+	if DefaultSanitizerDropElements["b"] {
+		t.Errorf("error DefaultSanitizerDropElements changed")
+	}
+}
@@ -0,0 +1,150 @@
+package textproc
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Lexicon is a set of multi-syllable words, used for word segmentation.
+// Words are stored in lower case with old style tone marks,
+// syllables in a word are separated by a space.
+type Lexicon struct {
+	words map[string]bool
+	// maxSyllables is the number of syllables of the longest word
+	maxSyllables int
+	// maxRunes is the number of chars of the longest word (without spaces),
+	// used to match words of scripts that do not use spaces (CJK, Thai)
+	maxRunes int
+}
+
+// NewLexicon returns a Lexicon contains the input words,
+// syllables in a word can be separated by spaces or underscores
+func NewLexicon(words []string) *Lexicon {
+	lex := &Lexicon{words: make(map[string]bool, len(words))}
+	for _, word := range words {
+		lex.Add(word)
+	}
+	return lex
+}
+
+// Add adds a word to the lexicon, it is not safe to call Add while the
+// lexicon is being used by other goroutines
+func (lex *Lexicon) Add(word string) {
+	syllables := strings.Fields(strings.ReplaceAll(word, "_", " "))
+	if len(syllables) == 0 {
+		return
+	}
+	for i, s := range syllables {
+		syllables[i] = lexiconKey(s)
+	}
+	lex.words[strings.Join(syllables, " ")] = true
+	if len(syllables) > lex.maxSyllables {
+		lex.maxSyllables = len(syllables)
+	}
+	lex.maxRunes = max(lex.maxRunes, utf8.RuneCountInString(strings.Join(syllables, "")))
+}
+
+// Contains returns true if the word is in the lexicon,
+// syllables in the word can be separated by spaces or underscores
+func (lex *Lexicon) Contains(word string) bool {
+	syllables := strings.Fields(strings.ReplaceAll(word, "_", " "))
+	for i, s := range syllables {
+		syllables[i] = lexiconKey(s)
+	}
+	return lex.words[strings.Join(syllables, " ")]
+}
+
+// Len returns number of words in the lexicon
+func (lex *Lexicon) Len() int { return len(lex.words) }
+
+// lexiconKey converts a syllable to the form that is stored in a Lexicon
+func lexiconKey(syllable string) string {
+	return NormalizeVietnameseTone(strings.ToLower(syllable), ToneStyleOld)
+}
+
+// DefaultLexicon is the lexicon used by SegmentVietnamese,
+// callers can replace it with their own lexicon or Add words to it.
+var DefaultLexicon = NewLexicon(strings.Fields(defaultVietnameseLexicon))
+
+// Span is a word in the segmentation result, Begin and End are indexes
+// of the first syllable and after the last syllable of the word
+type Span struct {
+	Begin int
+	End   int
+}
+
+// Segmenter groups Vietnamese syllables to words by forward longest matching
+// with a lexicon
+type Segmenter struct {
+	// Lexicon default is DefaultLexicon
+	Lexicon *Lexicon
+	// Separator joins syllables of a word, default is "_"
+	Separator string
+}
+
+// SegmentVietnamese splits a text to list of words (punctuations removed),
+// syllables of a multi-syllable word are joined by "_",
+// example: "Thị trường chứng khoán" => ["Thị_trường", "chứng_khoán"].
+// The result can be passed to WordsToNGrams.
+func SegmentVietnamese(text string) []string {
+	return Segmenter{}.Segment(text)
+}
+
+// Segment splits a text to list of words, see SegmentVietnamese
+func (s Segmenter) Segment(text string) []string {
+	sep := s.Separator
+	if sep == "" {
+		sep = "_"
+	}
+	syllables, boundaries := textToSyllables(text)
+	ret := make([]string, 0, len(syllables))
+	for _, span := range s.SegmentSpans(syllables, boundaries) {
+		ret = append(ret, strings.Join(syllables[span.Begin:span.End], sep))
+	}
+	return ret
+}
+
+// SegmentSpans splits syllables (result of TextToWords) to words,
+// a word will not span across a phrase boundary if boundaries is not nil
+// (boundaries[i] is true if there is a punctuation or a new line
+// between syllables[i-1] and syllables[i]).
+func (s Segmenter) SegmentSpans(syllables []string, boundaries []bool) []Span {
+	lex := s.Lexicon
+	if lex == nil {
+		lex = DefaultLexicon
+	}
+	keys := make([]string, len(syllables))
+	for i, syllable := range syllables {
+		keys[i] = lexiconKey(syllable)
+	}
+	ret := make([]Span, 0, len(syllables))
+	for i := 0; i < len(syllables); {
+		// limit is the index after the last syllable of the current phrase
+		limit := i + 1
+		for limit < len(syllables) && limit-i < lex.maxSyllables &&
+			!(boundaries != nil && boundaries[limit]) {
+			limit++
+		}
+		end := i + 1
+		for j := limit; j > i+1; j-- {
+			if lex.words[strings.Join(keys[i:j], " ")] {
+				end = j
+				break
+			}
+		}
+		ret = append(ret, Span{Begin: i, End: end})
+		i = end
+	}
+	return ret
+}
+
+// textToSyllables is TextToWords with phrase boundaries,
+// boundaries[i] is true if there is a punctuation or a new line
+// before syllables[i]
+func textToSyllables(text string) (syllables []string, boundaries []bool) {
+	for _, span := range textToWordSpans(text) {
+		syllables = append(syllables, span.word)
+		boundaries = append(boundaries, span.boundary)
+	}
+	return syllables, boundaries
+}
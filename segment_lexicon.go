@@ -0,0 +1,58 @@
+package textproc
+
+// defaultVietnameseLexicon is a small list of common Vietnamese
+// multi-syllable words (mostly in news), separated by white spaces
+const defaultVietnameseLexicon = `
+an_ninh an_toàn anh_em bài_viết bản_thân báo_cáo bảo_hiểm bảo_mật bảo_vệ
+bất_động_sản bầu_cử biến_động biện_pháp bình_thường bóng_đá bộ_trưởng
+bởi_vì bữa_ăn các_bạn cải_cách cải_thiện cạnh_tranh cán_bộ cảnh_sát
+cao_cấp cắt_giảm câu_chuyện cầu_thủ chất_lượng chỉ_số chiến_dịch
+chiến_lược chiến_tranh chính_phủ chính_quyền chính_sách chính_trị
+chủ_tịch chúng_ta chúng_tôi chuyên_gia chứng_khoán chương_trình
+có_thể công_an công_bố công_nghệ công_nghiệp công_ty công_việc cổ_phiếu
+cổ_đông cơ_hội cơ_quan cơ_sở cung_cấp cuộc_sống cửa_hàng cho_biết
+dân_số dân_tộc dấu_hiệu dầu_khí dầu_thô dịch_bệnh dịch_vụ diễn_biến
+diễn_ra doanh_nghiệp doanh_thu dòng_tiền du_lịch dự_án dự_báo
+dữ_liệu đại_dịch đại_học đầu_tư đất_nước đề_xuất địa_phương điện_thoại
+điều_chỉnh điều_khoản điều_kiện điều_tra đối_tác đối_với đồng_thời
+đồng_tiền đơn_vị gia_đình giá_cả giao_dịch giao_thông giải_pháp
+giải_quyết giáo_dục giới_thiệu hàng_hóa hành_chính hạn_chế hiện_nay
+hiện_tại hoạt_động học_sinh hỗ_trợ hội_nghị hợp_đồng hợp_tác hướng_dẫn
+khách_hàng khả_năng khó_khăn khoa_học không_khí khu_vực kinh_doanh
+kinh_tế kỹ_thuật kết_quả kết_thúc kế_hoạch kiểm_soát kiểm_tra lãi_suất
+lãnh_đạo lao_động lịch_sử lĩnh_vực lợi_nhuận luật_sư mạng_xã_hội
+mặt_hàng máy_bay máy_tính minh_bạch mục_tiêu năng_lượng ngân_hàng
+ngân_sách người_dân nghiên_cứu ngoại_giao ngoại_tệ nguồn_vốn nguy_cơ
+nhà_đầu_tư nhà_máy nhà_nước nhân_dân nhân_viên nhận_định nhiên_liệu
+nhu_cầu như_vậy những_người nông_nghiệp nội_dung phát_triển phân_tích
+phản_hồi phương_pháp phục_hồi quan_hệ quan_trọng quản_lý quảng_cáo
+quốc_gia quốc_hội quốc_tế quy_định quyết_định sản_phẩm sản_xuất
+sinh_viên sức_khỏe suy_yếu tài_chính tài_khoản tài_liệu tăng_trưởng
+tập_đoàn thanh_toán thanh_tra thành_phố thành_viên thế_giới thị_trường
+thiết_kế thông_tin thời_gian thời_tiết thu_nhập thủ_tướng thương_lượng
+thương_mại tiền_tệ tiến_triển tiếp_theo tiếp_tục tìm_kiếm tình_hình
+tổ_chức tổng_công_ty tổng_thống toàn_bộ trái_phiếu trợ_giúp trung_bình
+trung_tâm trường_hợp trực_tiếp tuy_nhiên tương_đương tương_lai tử_vong
+ủy_ban ủy_ban_chứng_khoán_nhà_nước vấn_đề vi_phạm việc_làm viện_trợ
+vừa_qua xã_hội xảy_ra xây_dựng xu_hướng xuất_khẩu nhập_khẩu xử_phạt
+xử_lý y_tế Việt_Nam Hà_Nội Hồ_Chí_Minh thành_phố_Hồ_Chí_Minh Đà_Nẵng
+Hải_Phòng Cần_Thơ Trung_Quốc Nhật_Bản Hàn_Quốc Ấn_Độ Hoa_Kỳ New_York
+`
+
+// defaultDictionaryLexicon is a small list of common Chinese (simplified and
+// traditional), Japanese and Thai words, separated by white spaces
+const defaultDictionaryLexicon = `
+中文 中国 中國 中华 中華 人民 共和国 中华人民共和国 北京 上海 香港 台湾 台灣
+越南 美国 美國 英国 英國 日本 韩国 韓國 世界 国家 國家 政府 经济 經濟
+我们 我們 你们 你們 他们 他們 你好 今天 明天 昨天 时间 時間 问题 問題
+工作 学习 學習 学生 學生 老师 老師 朋友 喜欢 喜歡 知道 没有 沒有 什么 什麼
+因为 因為 所以 但是 可以 已经 已經 现在 現在 电脑 電腦 手机 手機 公司 大学
+大學 新闻 新聞 天气 天氣 语言 語言 汉语 漢語 简体 簡體 繁体 繁體 汉字 漢字
+东京 東京 大阪 京都 日本語 東京タワー タワー 会社 仕事 電話 先生 学校 今日
+明日 昨日 天気 言葉 食べます 行きます 見ます します ありがとう こんにちは
+ภาษา ไทย ภาษาไทย ง่าย ยาก สวัสดี ครับ ค่ะ ขอบคุณ ประเทศ ประเทศไทย กรุงเทพ
+คน ไป มา กิน ข้าว น้ำ ดี มาก ไม่ ใช่ เป็น อยู่ ที่ และ หรือ ของ ใน กับ ได้
+วัน นี้ วันนี้ พรุ่งนี้ เมื่อวาน เรา เขา ฉัน ผม คุณ รัก ทำงาน โรงเรียน นักเรียน
+ครู บ้าน รถ เวลา เงิน ตลาด อาหาร อร่อย ร้อน เย็น สบาย สบายดี อังกฤษ
+ภาษาอังกฤษ จีน ญี่ปุ่น เวียดนาม
+`
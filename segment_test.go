@@ -0,0 +1,44 @@
+package textproc
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSegmentVietnamese(t *testing.T) {
+	text := `Ngày 30/09, Thanh tra Ủy ban Chứng khoán Nhà nước (UBCKNN) đã quyết định
+xử phạt vi phạm hành chính trong lĩnh vực chứng khoán và thị trường chứng khoán.`
+	words := SegmentVietnamese(text)
+	jbs, err := json.Marshal(words)
+	if err != nil {
+		t.Error(err)
+	}
+	if string(jbs) != `["Ngày","30/09","Thanh_tra","Ủy_ban_Chứng_khoán_Nhà_nước","UBCKNN","đã","quyết_định","xử_phạt","vi_phạm","hành_chính","trong","lĩnh_vực","chứng_khoán","và","thị_trường","chứng_khoán"]` {
+		t.Error(string(jbs))
+	}
+
+	nGrams := WordsToNGrams(SegmentVietnamese("thị trường chứng khoán"), 2)
+	if len(nGrams) != 1 || nGrams["thị_trường chứng_khoán"] != 1 {
+		t.Errorf("error WordsToNGrams segmented: %v", nGrams)
+	}
+}
+
+func TestSegmenter(t *testing.T) {
+	lex := NewLexicon([]string{"đả bại", "sức_mạnh", "bây giờ"})
+	if !lex.Contains("Sức mạnh") || lex.Contains("mạnh") || lex.Len() != 3 {
+		t.Error("error Lexicon Contains")
+	}
+	segmenter := Segmenter{Lexicon: lex, Separator: " "}
+	// a word does not span across punctuation
+	words := segmenter.Segment(`thêm sức mạnh để đả bại. Bây, giờ`)
+	jbs, _ := json.Marshal(words)
+	if string(jbs) != `["thêm","sức mạnh","để","đả bại","Bây","giờ"]` {
+		t.Error(string(jbs))
+	}
+
+	spans := segmenter.SegmentSpans([]string{"bây", "giờ", "hoà"}, nil)
+	if len(spans) != 2 || spans[0] != (Span{Begin: 0, End: 2}) ||
+		spans[1] != (Span{Begin: 2, End: 3}) {
+		t.Errorf("error SegmentSpans: %v", spans)
+	}
+}
@@ -0,0 +1,177 @@
+package textproc
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Sentence is a result of SplitSentences, Start and End are byte offsets
+// of the sentence in the text (text[Start:End] == Text)
+type Sentence struct {
+	Text  string
+	Start int
+	End   int
+}
+
+// SentenceAbbreviations is a set of lowercase abbreviations (without the
+// last dot) that do not end a sentence even if they are followed by a space
+// and an uppercase word, example: "Mr. Smith", "TP. Hồ Chí Minh".
+// Callers can add their own abbreviations.
+var SentenceAbbreviations = toMapStrings(
+	"mr", "mrs", "ms", "dr", "prof", "sr", "jr", "st", "mt", "vs", "no",
+	"e.g", "i.e", "inc", "ltd", "co", "corp", "jan", "feb", "mar", "apr",
+	"jun", "jul", "aug", "sep", "sept", "oct", "nov", "dec",
+	"tp", "q", "p", "tx", "gs", "pgs", "ts", "ths", "bs", "ks", "th.s", "tt",
+	"v.v",
+)
+
+func toMapStrings(words ...string) map[string]bool {
+	ret := make(map[string]bool, len(words))
+	for _, word := range words {
+		ret[word] = true
+	}
+	return ret
+}
+
+// isSentenceTerminator returns true for chars that can end a sentence
+func isSentenceTerminator(char rune) bool {
+	switch char {
+	case '.', '!', '?', '…':
+		return true
+	}
+	return false
+}
+
+// isSentenceCloser returns true for chars that can follow a sentence
+// terminator in the same sentence, example: He said "Go now."
+func isSentenceCloser(char rune) bool {
+	switch char {
+	case '"', '\'', '”', '’', ')', ']', '}', '»':
+		return true
+	}
+	return false
+}
+
+// SplitSentences splits a text to sentences, a sentence ends with
+// a terminator (".", "!", "?", "…") followed by a space and a word that
+// is not lowercase, or a line break followed by a word that is not
+// lowercase, or an empty line.
+// Dots that are not followed by a space ("2.0", "TP.HCM", URLs) and dots of
+// SentenceAbbreviations do not end a sentence.
+func SplitSentences(text string) []Sentence {
+	ret := make([]Sentence, 0)
+	emit := func(start int, end int) {
+		for start < end {
+			char, size := utf8.DecodeRuneInString(text[start:])
+			if !unicode.IsSpace(char) {
+				break
+			}
+			start += size
+		}
+		for end > start {
+			char, size := utf8.DecodeLastRuneInString(text[:end])
+			if !unicode.IsSpace(char) {
+				break
+			}
+			end -= size
+		}
+		if start < end {
+			ret = append(ret, Sentence{Text: text[start:end], Start: start, End: end})
+		}
+	}
+
+	start := 0
+	for i := 0; i < len(text); {
+		char, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case char == '\n':
+			lineEnd := i
+			next, nNewlines := skipSpaces(text, i)
+			if nNewlines >= 2 || !startsWithLower(text[next:]) {
+				emit(start, lineEnd)
+				start = next
+			}
+			i = next
+		case isSentenceTerminator(char):
+			end := i + size
+			onlyOneDot := char == '.'
+			for end < len(text) {
+				c, s := utf8.DecodeRuneInString(text[end:])
+				if !isSentenceTerminator(c) {
+					break
+				}
+				onlyOneDot = false
+				end += s
+			}
+			for end < len(text) {
+				c, s := utf8.DecodeRuneInString(text[end:])
+				if !isSentenceCloser(c) {
+					break
+				}
+				end += s
+			}
+			if end < len(text) {
+				c, _ := utf8.DecodeRuneInString(text[end:])
+				if !unicode.IsSpace(c) { // "2.0", "TP.HCM", "http://a.vn"
+					i = end
+					continue
+				}
+			}
+			next, _ := skipSpaces(text, end)
+			isEnd := !startsWithLower(text[next:])
+			if isEnd && onlyOneDot && isAbbreviation(lastToken(text[start:i])) {
+				isEnd = false
+			}
+			if isEnd {
+				emit(start, end)
+				start = end
+			}
+			i = end
+		default:
+			i += size
+		}
+	}
+	emit(start, len(text))
+	return ret
+}
+
+// skipSpaces returns offset of the first non space char from i,
+// and number of line breaks that were skipped
+func skipSpaces(text string, i int) (next int, nNewlines int) {
+	for i < len(text) {
+		char, size := utf8.DecodeRuneInString(text[i:])
+		if !unicode.IsSpace(char) {
+			break
+		}
+		if char == '\n' {
+			nNewlines++
+		}
+		i += size
+	}
+	return i, nNewlines
+}
+
+// startsWithLower returns true if the text starts with a lowercase letter
+func startsWithLower(text string) bool {
+	char, _ := utf8.DecodeRuneInString(text)
+	return unicode.IsLower(char)
+}
+
+// lastToken returns the last space separated token of the text,
+// leading punctuations are removed, example: "said (Mr" => "Mr"
+func lastToken(text string) string {
+	if i := strings.LastIndexFunc(text, unicode.IsSpace); i != -1 {
+		text = text[i+1:]
+	}
+	return strings.TrimLeftFunc(text, func(r rune) bool { return !AlphaNumeric[r] })
+}
+
+// isAbbreviation returns true if the token is in SentenceAbbreviations or
+// is an uppercase initial, example: "J" in "J. Smith"
+func isAbbreviation(token string) bool {
+	if len(token) == 1 && 'A' <= token[0] && token[0] <= 'Z' {
+		return true
+	}
+	return SentenceAbbreviations[strings.ToLower(token)]
+}
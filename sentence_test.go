@@ -0,0 +1,48 @@
+package textproc
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSplitSentences(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		out string // JSON of sentence texts
+	}{
+		{
+			in:  paragraphs[4],
+			out: `["Có thánh này, chắc chắn \"Sẻ đệ\" (NDB 2.0) sẽ thêm sức mạnh để đả bại Sơ Luyến.","Trực tiếp ngay bây giờ trên http://www.gametv1.vn.","______ Ahihi"]`,
+		},
+		{
+			in:  "Mr. Smith đến TP.HCM lúc 9.30 sáng. Ông gặp TS. Nguyễn Văn A. tại Q. 1, sau đó đi ăn, uống v.v. và nghỉ. Rồi về!",
+			out: `["Mr. Smith đến TP.HCM lúc 9.30 sáng.","Ông gặp TS. Nguyễn Văn A. tại Q. 1, sau đó đi ăn, uống v.v. và nghỉ.","Rồi về!"]`,
+		},
+		{
+			in:  "Chợ bán rau, thịt, cá v.v. Sau đó đóng cửa.",
+			out: `["Chợ bán rau, thịt, cá v.v. Sau đó đóng cửa."]`,
+		},
+		{
+			in:  `He said "Go now." Then he left... Why? "I don't know!" she said.`,
+			out: `["He said \"Go now.\"","Then he left...","Why?","\"I don't know!\" she said."]`,
+		},
+		{
+			in:  "VE bị phạt 100 triệu đồng\nNgày 30/09, Thanh tra UBCKNN đã quyết định xử phạt\nvi phạm hành chính.\n\nthe end",
+			out: `["VE bị phạt 100 triệu đồng","Ngày 30/09, Thanh tra UBCKNN đã quyết định xử phạt\nvi phạm hành chính.","the end"]`,
+		},
+		{in: "  ", out: `[]`},
+	} {
+		sentences := SplitSentences(test.in)
+		texts := make([]string, 0)
+		for _, s := range sentences {
+			texts = append(texts, s.Text)
+			if test.in[s.Start:s.End] != s.Text {
+				t.Errorf("error Sentence offsets: %+v", s)
+			}
+		}
+		jbs, _ := json.Marshal(texts)
+		if string(jbs) != test.out {
+			t.Errorf("error SplitSentences: real: %s, expected: %s", jbs, test.out)
+		}
+	}
+}
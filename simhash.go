@@ -0,0 +1,189 @@
+package textproc
+
+import (
+	"math/bits"
+	"sort"
+	"sync"
+)
+
+// simHashNGramSize is the n-gram size that SimHash uses as features
+const simHashNGramSize = 3
+
+// SimHash returns a 64-bit fingerprint of the text, near duplicate texts
+// have fingerprints with small HammingDistance.
+// Features are word 3-grams (or words if the text is too short).
+func SimHash(text string) uint64 {
+	nGrams := TextToNGrams(text, simHashNGramSize)
+	if len(nGrams) == 0 {
+		nGrams = TextToNGrams(text, 1)
+	}
+	return SimHashNGrams(nGrams)
+}
+
+// SimHashNGrams returns a 64-bit fingerprint of a set of weighted features,
+// example input is the result of TextToNGrams or WordsToNGrams
+func SimHashNGrams(nGrams map[string]int) uint64 {
+	var weights [64]int
+	for nGram, count := range nGrams {
+		h := uint64(HashTextToInt(nGram))
+		for i := 0; i < 64; i++ {
+			if h&(1<<uint(i)) != 0 {
+				weights[i] += count
+			} else {
+				weights[i] -= count
+			}
+		}
+	}
+	var ret uint64
+	for i, w := range weights {
+		if w > 0 {
+			ret |= 1 << uint(i)
+		}
+	}
+	return ret
+}
+
+// HammingDistance returns number of different bits between 2 fingerprints
+func HammingDistance(a uint64, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// SimHashMatch is a result of SimHashIndex_Query
+type SimHashMatch struct {
+	ID       string
+	Hash     uint64
+	Distance int
+}
+
+// SimHashIndex finds stored fingerprints that are within MaxDistance bits of
+// a query fingerprint. The 64 bits are split into MaxDistance+1 blocks,
+// (by pigeonhole principle) a near fingerprint must have at least one
+// block that is exactly the same as the query, so each block has its own
+// table and a query only needs to check fingerprints in the matched buckets.
+// SimHashIndex is safe for concurrent use.
+type SimHashIndex struct {
+	maxDistance int
+	blocks      []simHashBlock
+	mu          sync.RWMutex
+	tables      []map[uint64][]string // one table for each block
+	hashes      map[string]uint64     // id to fingerprint
+}
+
+// simHashBlock is a contiguous range of bits in a fingerprint
+type simHashBlock struct {
+	shift uint
+	mask  uint64
+}
+
+// NewSimHashIndex returns an empty index, maxDistance must be in [0, 63]
+// (it will be clamped), a small maxDistance (3 to 6) makes faster queries.
+func NewSimHashIndex(maxDistance int) *SimHashIndex {
+	if maxDistance < 0 {
+		maxDistance = 0
+	}
+	if maxDistance > 63 {
+		maxDistance = 63
+	}
+	nBlocks := maxDistance + 1
+	idx := &SimHashIndex{
+		maxDistance: maxDistance,
+		blocks:      make([]simHashBlock, nBlocks),
+		tables:      make([]map[uint64][]string, nBlocks),
+		hashes:      make(map[string]uint64),
+	}
+	shift := uint(0)
+	for i := 0; i < nBlocks; i++ {
+		size := uint(64 / nBlocks)
+		if i < 64%nBlocks {
+			size++
+		}
+		idx.blocks[i] = simHashBlock{shift: shift, mask: (1<<size - 1) << shift}
+		idx.tables[i] = make(map[uint64][]string)
+		shift += size
+	}
+	return idx
+}
+
+// MaxDistance returns the maxDistance passed to NewSimHashIndex
+func (idx *SimHashIndex) MaxDistance() int { return idx.maxDistance }
+
+// Len returns number of fingerprints in the index
+func (idx *SimHashIndex) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.hashes)
+}
+
+// Add stores a fingerprint, an existing fingerprint with the same id
+// will be replaced
+func (idx *SimHashIndex) Add(id string, hash uint64) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if _, found := idx.hashes[id]; found {
+		idx.remove(id)
+	}
+	idx.hashes[id] = hash
+	for i, block := range idx.blocks {
+		key := hash & block.mask
+		idx.tables[i][key] = append(idx.tables[i][key], id)
+	}
+}
+
+// Remove deletes the fingerprint with the id, does nothing if not found
+func (idx *SimHashIndex) Remove(id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(id)
+}
+
+// remove must be called while holding the lock
+func (idx *SimHashIndex) remove(id string) {
+	hash, found := idx.hashes[id]
+	if !found {
+		return
+	}
+	delete(idx.hashes, id)
+	for i, block := range idx.blocks {
+		key := hash & block.mask
+		bucket := idx.tables[i][key]
+		for j, bucketID := range bucket {
+			if bucketID == id {
+				bucket = append(bucket[:j], bucket[j+1:]...)
+				break
+			}
+		}
+		if len(bucket) == 0 {
+			delete(idx.tables[i], key)
+		} else {
+			idx.tables[i][key] = bucket
+		}
+	}
+}
+
+// Query returns all stored fingerprints within MaxDistance bits of the hash,
+// sorted by distance then by id
+func (idx *SimHashIndex) Query(hash uint64) []SimHashMatch {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	checked := make(map[string]bool)
+	ret := make([]SimHashMatch, 0)
+	for i, block := range idx.blocks {
+		for _, id := range idx.tables[i][hash&block.mask] {
+			if checked[id] {
+				continue
+			}
+			checked[id] = true
+			stored := idx.hashes[id]
+			if d := HammingDistance(hash, stored); d <= idx.maxDistance {
+				ret = append(ret, SimHashMatch{ID: id, Hash: stored, Distance: d})
+			}
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Distance != ret[j].Distance {
+			return ret[i].Distance < ret[j].Distance
+		}
+		return ret[i].ID < ret[j].ID
+	})
+	return ret
+}
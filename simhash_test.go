@@ -0,0 +1,60 @@
+package textproc
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestSimHash(t *testing.T) {
+	news1 := `Giá dầu Brent tương lai tăng 74 cent, tương đương 1,7%, lên 45,17 USD/thùng.
+Giá dầu WTI tương lai tăng 49 cent, tương đương 1,2%, lên 42,19 USD/thùng.
+Đầu phiên 5/8, giá của hai loại dầu đều có lúc tăng hơn 4%.
+Tồn kho dầu thô tại Mỹ trong tuần kết thúc ngày 31/7 giảm 7,4 triệu thùng.`
+	news2 := `Giá dầu Brent tương lai tăng 74 cent, tương đương 1,7%, lên 45,17 USD/thùng.
+Giá dầu WTI tương lai tăng 49 cent, tương đương 1,2%, lên 42,19 USD/thùng.
+Đầu phiên 5/8, giá của hai loại dầu đều có lúc tăng hơn 4%.
+Tồn kho dầu thô tại Mỹ trong tuần kết thúc ngày 31/7 giảm 7,5 triệu thùng!`
+	d12 := HammingDistance(SimHash(news1), SimHash(news2))
+	d13 := HammingDistance(SimHash(news1), SimHash(paragraphs[3]))
+	if !(d12 < 10 && d12 < d13) {
+		t.Errorf("error SimHash: near duplicate distance: %v, different distance: %v", d12, d13)
+	}
+	if SimHash("Sơ Luyến") == 0 {
+		t.Errorf("error SimHash short text")
+	}
+	if HammingDistance(0, 7) != 3 || HammingDistance(5, 5) != 0 {
+		t.Errorf("error HammingDistance")
+	}
+}
+
+func TestSimHashIndex(t *testing.T) {
+	idx := NewSimHashIndex(3)
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		idx.Add(fmt.Sprintf("random%v", i), rnd.Uint64())
+	}
+	base := uint64(0x0123456789abcdef)
+	idx.Add("base", base)
+	idx.Add("near2", base^(1<<2|1<<40))
+	idx.Add("near3", base^(1<<0|1<<31|1<<63))
+	idx.Add("far4", base^(1<<1|1<<17|1<<33|1<<50))
+	idx.Add("removed", base)
+	idx.Remove("removed")
+	if idx.Len() != 1004 {
+		t.Errorf("error SimHashIndex Len: real: %v, expected: 1004", idx.Len())
+	}
+
+	matches := idx.Query(base ^ 1<<10)
+	if len(matches) != 2 ||
+		matches[0].ID != "base" || matches[0].Distance != 1 ||
+		matches[1].ID != "near2" || matches[1].Distance != 3 {
+		t.Errorf("error SimHashIndex Query: %+v", matches)
+	}
+
+	matches = idx.Query(base)
+	if len(matches) != 3 || matches[0].ID != "base" ||
+		matches[1].ID != "near2" || matches[2].ID != "near3" {
+		t.Errorf("error SimHashIndex Query: %+v", matches)
+	}
+}
@@ -0,0 +1,85 @@
+package textproc
+
+import (
+	"strings"
+)
+
+// StopWords is a set of lowercase words that should be ignored in keyword
+// extraction, a multi-syllable stop word has syllables separated by a space.
+type StopWords map[string]bool
+
+// NewStopWords returns a StopWords contains the input words
+func NewStopWords(words ...string) StopWords {
+	sw := make(StopWords, len(words))
+	sw.Add(words...)
+	return sw
+}
+
+// Add adds words to the set, words are converted to lowercase
+func (sw StopWords) Add(words ...string) {
+	for _, word := range words {
+		if key := stopWordKey(word); key != "" {
+			sw[key] = true
+		}
+	}
+}
+
+// Merge returns a new set contains words in all input sets
+func (sw StopWords) Merge(others ...StopWords) StopWords {
+	ret := make(StopWords, len(sw))
+	for _, set := range append([]StopWords{sw}, others...) {
+		for word := range set {
+			ret[word] = true
+		}
+	}
+	return ret
+}
+
+// Contains returns true if the word (case insensitive) is a stop word,
+// syllables of the word can be separated by spaces or underscores
+// (result of SegmentVietnamese)
+func (sw StopWords) Contains(word string) bool {
+	if len(sw) == 0 {
+		return false
+	}
+	return sw[stopWordKey(word)]
+}
+
+func stopWordKey(word string) string {
+	return strings.TrimSpace(strings.ReplaceAll(strings.ToLower(word), "_", " "))
+}
+
+// FilterStopWords returns the input words without stop words
+func FilterStopWords(words []string, sw StopWords) []string {
+	ret := make([]string, 0, len(words))
+	for _, word := range words {
+		if !sw.Contains(word) {
+			ret = append(ret, word)
+		}
+	}
+	return ret
+}
+
+// VietnameseStopWords is the built-in Vietnamese stop words,
+// callers should Merge it with their own words instead of modifying it.
+var VietnameseStopWords = NewStopWords(strings.Split(`và,của,các,là,có,được,
+cho,với,những,này,đã,trong,một,không,thì,mà,để,khi,đến,từ,cũng,như,về,theo,
+lại,nên,nhưng,vì,bị,do,ra,vào,đó,nào,rằng,sẽ,đang,rất,hơn,nhiều,còn,vẫn,tại,
+trên,dưới,sau,trước,cùng,hay,hoặc,nếu,thế,gì,ai,đây,kia,ấy,chỉ,đều,mới,lên,
+xuống,nữa,hết,vậy,tới,qua,bởi,cả,luôn,ở,chưa,nhất,mỗi,việc,điều,cái,chiếc,
+thật,quá,lúc,nơi,đâu,sao,bao,ngay,vừa,tuy nhiên,bởi vì,vì vậy,do đó,
+trong khi,ngoài ra,cho nên,thế nhưng,tuy vậy,như vậy,chúng ta,chúng tôi`,
+	",")...)
+
+// EnglishStopWords is the built-in English stop words,
+// callers should Merge it with their own words instead of modifying it.
+var EnglishStopWords = NewStopWords(strings.Split(`a,about,above,after,again,
+against,all,am,an,and,any,are,as,at,be,because,been,before,being,below,
+between,both,but,by,can,could,did,do,does,doing,down,during,each,few,for,
+from,further,had,has,have,having,he,her,here,hers,herself,him,himself,his,
+how,i,if,in,into,is,it,its,itself,just,me,more,most,my,myself,no,nor,not,
+now,of,off,on,once,only,or,other,our,ours,ourselves,out,over,own,same,she,
+should,so,some,such,than,that,the,their,theirs,them,themselves,then,there,
+these,they,this,those,through,to,too,under,until,up,very,was,we,were,what,
+when,where,which,while,who,whom,why,will,with,would,you,your,yours,yourself,
+yourselves,s,t,don,can't,don't,i'm,it's,won't`, ",")...)
@@ -0,0 +1,43 @@
+package textproc
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStopWords(t *testing.T) {
+	if !VietnameseStopWords.Contains("Của") || !VietnameseStopWords.Contains("cho") ||
+		!VietnameseStopWords.Contains("tuy_nhiên") || VietnameseStopWords.Contains("") ||
+		!EnglishStopWords.Contains("The") || EnglishStopWords.Contains("sodium") {
+		t.Error("error built-in StopWords")
+	}
+	sw := VietnameseStopWords.Merge(EnglishStopWords, NewStopWords("Ahihi"))
+	if VietnameseStopWords.Contains("ahihi") || !sw.Contains("ahihi") || !sw.Contains("of") {
+		t.Error("error StopWords Merge")
+	}
+
+	words := FilterStopWords(TextToWords(paragraphs[4]), sw)
+	jbs, _ := json.Marshal(words)
+	if string(jbs) != `["thánh","chắc","chắn","Sẻ","đệ","NDB","2.0","thêm","sức","mạnh","đả","bại","Sơ","Luyến","Trực","tiếp","bây","giờ","http://www.gametv1.vn"]` {
+		t.Error(string(jbs))
+	}
+}
+
+func TestTextToNGramsWithOptions(t *testing.T) {
+	nGrams := TextToNGramsWithOptions("the father of the son said the truth", 2,
+		NGramOptions{StopWords: EnglishStopWords})
+	jbs, _ := json.Marshal(nGrams)
+	if string(jbs) != `{"son said":1}` {
+		t.Error(string(jbs))
+	}
+	nGrams = TextToNGramsWithOptions("của các nhà đầu tư và các thị trường", 3,
+		NGramOptions{StopWords: VietnameseStopWords})
+	jbs, _ = json.Marshal(nGrams)
+	if string(jbs) != `{"nhà đầu tư":1}` {
+		t.Error(string(jbs))
+	}
+	if len(TextToNGramsWithOptions(paragraphs[1], 2, NGramOptions{})) !=
+		len(TextToNGrams(paragraphs[1], 2)) {
+		t.Error("error TextToNGramsWithOptions without stop words")
+	}
+}
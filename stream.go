@@ -0,0 +1,149 @@
+package textproc
+
+import (
+	"bufio"
+	"io"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// redundantSpaceRemover is the streaming version of RemoveRedundantSpace
+type redundantSpaceRemover struct {
+	// pending is the spaces after the last content char of the line, they
+	// are only written if more content follows on the same line: a run of
+	// checkIsSpace chars becomes one space, other Unicode spaces (U+3000,
+	// U+2003, ...) are kept as RemoveRedundantSpace only trims them at
+	// line edges
+	pending          []byte
+	pendingLastSpace bool // last byte of pending is a collapsed space
+	pendingNewline   bool
+	lineHasContent   bool
+}
+
+// NewRedundantSpaceRemover returns a transformer that does the same thing as
+// RemoveRedundantSpace, it can be chained with other transformers by
+// transform.Chain or used with transform.NewReader, transform.NewWriter.
+// The transformer is not safe for concurrent use.
+func NewRedundantSpaceRemover() transform.Transformer {
+	return &redundantSpaceRemover{}
+}
+
+func (t *redundantSpaceRemover) Reset() {
+	*t = redundantSpaceRemover{}
+}
+
+func (t *redundantSpaceRemover) Transform(dst, src []byte, atEOF bool) (
+	nDst int, nSrc int, err error) {
+	for nSrc < len(src) {
+		char, size := utf8.DecodeRune(src[nSrc:])
+		if char == utf8.RuneError && !atEOF && !utf8.FullRune(src[nSrc:]) {
+			return nDst, nSrc, transform.ErrShortSrc
+		}
+		switch {
+		case checkIsSpace(char):
+			if t.lineHasContent && !t.pendingLastSpace {
+				t.pending = append(t.pending, ' ')
+				t.pendingLastSpace = true
+			}
+		case char == '\n':
+			if t.lineHasContent {
+				t.pendingNewline = true
+			}
+			t.pending, t.pendingLastSpace, t.lineHasContent = t.pending[:0], false, false
+		case unicode.IsSpace(char):
+			if t.lineHasContent {
+				t.pending = append(t.pending, src[nSrc:nSrc+size]...)
+				t.pendingLastSpace = false
+			}
+		default:
+			// pending can be longer than dst, it is written in many calls
+			n := copy(dst[nDst:], t.pending)
+			nDst += n
+			t.pending = t.pending[n:]
+			if len(t.pending) > 0 || nDst+1+size > len(dst) {
+				return nDst, nSrc, transform.ErrShortDst
+			}
+			if t.pendingNewline {
+				dst[nDst] = '\n'
+				nDst++
+			}
+			t.pendingNewline, t.pendingLastSpace = false, false
+			nDst += copy(dst[nDst:], src[nSrc:nSrc+size])
+			t.lineHasContent = true
+		}
+		nSrc += size
+	}
+	// same as RemoveRedundantSpace, keep the newline after the last line
+	if atEOF && t.pendingNewline {
+		if nDst+1 > len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		dst[nDst] = '\n'
+		nDst++
+		t.pendingNewline = false
+	}
+	return nDst, nSrc, nil
+}
+
+// NewVietnamDiacriticRemover returns a transformer that does the same thing
+// as RemoveVietnamDiacritic, example: Đào => Dao.
+// The transformer is not safe for concurrent use.
+func NewVietnamDiacriticRemover() transform.Transformer {
+	return transform.Chain(
+		norm.NFKD, runes.Remove(runes.In(unicode.Mn)), norm.NFKC,
+		runes.Map(removeVietnamDiacritic))
+}
+
+// NewWordScanner returns a scanner that reads words from the reader,
+// words are split by the same rules as TextToWords
+func NewWordScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(ScanTextWords)
+	return scanner
+}
+
+// ScanTextWords is a bufio.SplitFunc that returns each word (punctuations
+// removed) in the same way as TextToWords
+func ScanTextWords(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	for {
+		// skip leading spaces
+		start := 0
+		for start < len(data) {
+			char, size := utf8.DecodeRune(data[start:])
+			if !checkIsSpaceNL(char) {
+				break
+			}
+			start += size
+		}
+		// scan until space, marking end of word
+		end := -1
+		for i := start; i < len(data); {
+			char, size := utf8.DecodeRune(data[i:])
+			if char == utf8.RuneError && !atEOF && !utf8.FullRune(data[i:]) {
+				break
+			}
+			if checkIsSpaceNL(char) {
+				end = i
+				break
+			}
+			i += size
+		}
+		if end == -1 {
+			if !atEOF || start >= len(data) {
+				return advance + start, nil, nil // request more data
+			}
+			end = len(data)
+		}
+		word, begin, wordEnd := trimNonAlphaNumeric(string(data[start:end]))
+		if word != "" {
+			return advance + end, data[start+begin : start+wordEnd], nil
+		}
+		// the field has no AlphaNumeric char, skip it
+		advance += end
+		data = data[end:]
+	}
+}
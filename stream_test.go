@@ -0,0 +1,67 @@
+package textproc
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"golang.org/x/text/transform"
+)
+
+func TestRedundantSpaceRemover(t *testing.T) {
+	for _, in := range []string{
+		paragraphs[3],
+		"a\n",
+		" \t\n a  b\t\n\n\nc \n  \n",
+		"\n",
+		"Google có các thứ tiếng:  \nEnglish\n    \nFrançais\n    \n中文（繁體）\n  ",
+		"\u3000 a\u2003 \u3000b \u00a0\u3000\n\u2003\n \u3000c\u3000",
+		"a \u3000\u3000 \t b\u3000\u2003 \u3000 \t\u3000",
+		strings.Repeat("a\u3000", 3000) + strings.Repeat("\u3000", 5000) + "b",
+	} {
+		e := RemoveRedundantSpace(in)
+		// small reads and writes to test ErrShortSrc and ErrShortDst handling
+		reader := transform.NewReader(
+			iotest.OneByteReader(strings.NewReader(in)), NewRedundantSpaceRemover())
+		out, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != e {
+			t.Errorf("error RedundantSpaceRemover: real: %q, expected: %q", out, e)
+		}
+	}
+}
+
+func TestVietnamDiacriticRemover(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer := transform.NewWriter(buf, transform.Chain(
+		NewRedundantSpaceRemover(), NewVietnamDiacriticRemover()))
+	for _, chunk := range []string{"NGUYỄN  NGỌC ", " THUẬN\n\n", "Hải Ðường"} {
+		_, _ = writer.Write([]byte(chunk))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if r, e := buf.String(), "NGUYEN NGOC THUAN\nHai Duong"; r != e {
+		t.Errorf("error VietnamDiacriticRemover: real: %q, expected: %q", r, e)
+	}
+}
+
+func TestWordScanner(t *testing.T) {
+	text := paragraphs[4] + "\n" + paragraphs[3]
+	scanner := NewWordScanner(iotest.HalfReader(strings.NewReader(text)))
+	var words []string
+	for scanner.Scan() {
+		words = append(words, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if e := TextToWords(text); !reflect.DeepEqual(words, e) {
+		t.Errorf("error WordScanner: real: %v, expected: %v", words, e)
+	}
+}
@@ -1,13 +1,11 @@
 package textproc
 
 import (
-	"hash/fnv"
 	"math/rand"
 	"strings"
 	"time"
-	"unicode"
+	"unicode/utf8"
 
-	"golang.org/x/text/runes"
 	"golang.org/x/text/transform"
 	"golang.org/x/text/unicode/norm"
 )
@@ -78,9 +76,23 @@ func checkIsSpaceNL(char rune) bool {
 
 // HashTextToInt is a unique and fast hash func
 func HashTextToInt(word string) int64 {
-	h := fnv.New64a()
-	_, _ = h.Write([]byte(word))
-	return int64(h.Sum64())
+	return int64(fnv64a(word))
+}
+
+// FNV-1a 64 bit constants, same as hash/fnv
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// fnv64a is hash/fnv New64a without converting the string to []byte
+func fnv64a(s string) uint64 {
+	h := uint64(fnvOffset64)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= fnvPrime64
+	}
+	return h
 }
 
 func GenRandomWord(minLen int, maxLen int, charList []rune) string {
@@ -118,32 +130,75 @@ func TextToWords(text string) []string {
 	ret := make([]string, 0)
 	wordsWithPun := strings.FieldsFunc(text, checkIsSpaceNL)
 	for _, wordWP := range wordsWithPun {
-		runes := []rune(wordWP)
-		firstAlphaNumeric := -1
-		for i, r := range runes {
-			if AlphaNumeric[r] {
-				firstAlphaNumeric = i
-				break
-			}
+		word, _, _ := trimNonAlphaNumeric(wordWP)
+		if word == "" {
+			continue
 		}
-		if firstAlphaNumeric == -1 {
+		ret = append(ret, word)
+	}
+	return ret
+}
+
+// wordSpan is a word in a text (result of textToWordSpans)
+type wordSpan struct {
+	word  string
+	start int // byte offset of the word in the text
+	end   int
+	// boundary is true if there is a punctuation or a new line
+	// between this word and the previous word
+	boundary bool
+}
+
+// textToWordSpans splits a text to words in the same way as TextToWords,
+// but also returns the positions of words and phrase boundaries
+func textToWordSpans(text string) []wordSpan {
+	ret := make([]wordSpan, 0)
+	isBoundary := false
+	for i := 0; i < len(text); {
+		char, size := utf8.DecodeRuneInString(text[i:])
+		if checkIsSpaceNL(char) {
+			if char == '\n' {
+				isBoundary = true
+			}
+			i += size
 			continue
 		}
-		lastAlphaNumeric := len(runes)
-		for i := len(runes) - 1; i >= 0; i-- {
-			if AlphaNumeric[runes[i]] {
-				lastAlphaNumeric = i
+		j := i + size
+		for j < len(text) {
+			char, size = utf8.DecodeRuneInString(text[j:])
+			if checkIsSpaceNL(char) {
 				break
 			}
+			j += size
 		}
-		if lastAlphaNumeric == len(runes) {
-			continue
+		wordWP := text[i:j]
+		word, begin, end := trimNonAlphaNumeric(wordWP)
+		if word == "" {
+			isBoundary = true
+		} else {
+			ret = append(ret, wordSpan{word: word, start: i + begin, end: i + end,
+				boundary: len(ret) > 0 && (isBoundary || begin > 0)})
+			isBoundary = end < len(wordWP)
 		}
-		ret = append(ret, string(runes[firstAlphaNumeric:lastAlphaNumeric+1]))
+		i = j
 	}
 	return ret
 }
 
+// trimNonAlphaNumeric removes leading and trailing chars that are not in
+// AlphaNumeric, returns the trimmed word and byte offsets of the trimmed
+// word in the input (begin == end if the word has no AlphaNumeric char)
+func trimNonAlphaNumeric(wordWP string) (word string, begin int, end int) {
+	begin = strings.IndexFunc(wordWP, func(r rune) bool { return AlphaNumeric[r] })
+	if begin == -1 {
+		return "", 0, 0
+	}
+	end = strings.LastIndexFunc(wordWP, func(r rune) bool { return AlphaNumeric[r] })
+	_, lastSize := utf8.DecodeRuneInString(wordWP[end:])
+	end += lastSize
+	return wordWP[begin:end], begin, end
+}
+
 // WordsToNGrams creates a set of n-gram from input words,
 // (A n-gram is a contiguous sequence of n words)
 func WordsToNGrams(words []string, n int) map[string]int {
@@ -162,16 +217,186 @@ func TextToNGrams(text string, n int) map[string]int {
 	return WordsToNGrams(words, n)
 }
 
+// TextToWordsWithBoundaries is TextToWords but also returns boundaries:
+// indexes of words that start a new sentence (SplitSentences) or a new
+// line (the first word is not a boundary), it can be used as
+// NGramOptions_Boundaries,
+// example: "Hi, Mr. Ann. Bye\nbob" => ["Hi", "Mr", "Ann", "Bye", "bob"], [3, 4]
+func TextToWordsWithBoundaries(text string) (words []string, boundaries []int) {
+	words, boundaries = make([]string, 0), make([]int, 0)
+	sentences := SplitSentences(text)
+	nextSentence := 0 // index of the next sentence start to check
+	prevEnd := 0      // end of the previous word
+	for i, span := range textToWordSpans(text) {
+		isBoundary := false
+		for nextSentence < len(sentences) && sentences[nextSentence].Start <= span.start {
+			if sentences[nextSentence].Start >= prevEnd {
+				isBoundary = true
+			}
+			nextSentence++
+		}
+		if strings.Contains(text[prevEnd:span.start], "\n") {
+			isBoundary = true
+		}
+		if i > 0 && isBoundary {
+			boundaries = append(boundaries, i)
+		}
+		words = append(words, span.word)
+		prevEnd = span.end
+	}
+	return words, boundaries
+}
+
+// NGramOptions configures WordsToNGramsWithOptions
+type NGramOptions struct {
+	// StopWords: skip n-grams that start or end with a stop word
+	StopWords StopWords
+	// Boundaries are sorted indexes of words that start a new sentence or
+	// line (result of TextToWordsWithBoundaries), n-grams do not cross them
+	Boundaries []int
+	// StopAtBoundaries makes TextToNGramsWithOptions and TextToNGramRange
+	// find Boundaries in the text, so n-grams do not cross sentences or lines
+	StopAtBoundaries bool
+}
+
+// phrases returns phrase index of each word: number of Boundaries that are
+// less than or equal to the word index, returns nil if there is no boundary
+func (opts NGramOptions) phrases(nWords int) []int {
+	if len(opts.Boundaries) == 0 {
+		return nil
+	}
+	ret := make([]int, nWords)
+	phrase, next := 0, 0
+	for i := range ret {
+		for next < len(opts.Boundaries) && opts.Boundaries[next] <= i {
+			phrase++
+			next++
+		}
+		ret[i] = phrase
+	}
+	return ret
+}
+
+// allowed returns false if the n-gram that starts at words[first] and ends
+// at words[last] crosses a boundary or starts or ends with a stop word
+func (opts NGramOptions) allowed(words []string, phrases []int, first int, last int) bool {
+	if phrases != nil && phrases[first] != phrases[last] {
+		return false
+	}
+	return !opts.StopWords.Contains(words[first]) && !opts.StopWords.Contains(words[last])
+}
+
+// WordsToNGramsWithOptions is WordsToNGrams with options
+func WordsToNGramsWithOptions(words []string, n int, opts NGramOptions) map[string]int {
+	result := make(map[string]int, len(words))
+	phrases := opts.phrases(len(words))
+	for i := 0; i < len(words)-n+1; i++ {
+		if n > 0 && !opts.allowed(words, phrases, i, i+n-1) {
+			continue
+		}
+		nGram := strings.Join(words[i:i+n], " ")
+		result[nGram] += 1
+	}
+	return result
+}
+
+// TextToNGramsWithOptions is TextToNGrams with options
+func TextToNGramsWithOptions(text string, n int, opts NGramOptions) map[string]int {
+	words := opts.textToWords(text)
+	return WordsToNGramsWithOptions(words, n, opts)
+}
+
+// textToWords returns lowercase words of the text, Boundaries is set to the
+// text boundaries if StopAtBoundaries is true
+func (opts *NGramOptions) textToWords(text string) []string {
+	if !opts.StopAtBoundaries {
+		return TextToWords(strings.ToLower(text))
+	}
+	// sentences are split before lowercasing, SplitSentences needs the case
+	words, boundaries := TextToWordsWithBoundaries(text)
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	opts.Boundaries = boundaries
+	return words
+}
+
+// WordsToNGramRange creates a set of n-grams for all n in [minN, maxN],
+// example minN=1, maxN=2: ["a", "b"] => {"a", "b", "a b"}
+func WordsToNGramRange(words []string, minN int, maxN int, opts NGramOptions) map[string]int {
+	result := make(map[string]int, len(words)*max(maxN-minN+1, 0))
+	phrases := opts.phrases(len(words))
+	for i := range words {
+		for n := max(minN, 1); n <= maxN && i+n <= len(words); n++ {
+			if !opts.allowed(words, phrases, i, i+n-1) {
+				continue
+			}
+			result[strings.Join(words[i:i+n], " ")] += 1
+		}
+	}
+	return result
+}
+
+// TextToNGramRange creates a set of n-grams (lowercase) for all n in
+// [minN, maxN] from input text, the text is only split to words once
+func TextToNGramRange(text string, minN int, maxN int, opts NGramOptions) map[string]int {
+	words := opts.textToWords(text)
+	return WordsToNGramRange(words, minN, maxN, opts)
+}
+
+// WordsToSkipGrams creates a set of k-skip-n-grams: sequences of n words in
+// order that skip at most k words in total (contiguous n-grams included),
+// example n=2, k=1: ["a", "b", "c"] => {"a b", "b c", "a c"}
+func WordsToSkipGrams(words []string, n int, k int) map[string]int {
+	return WordsToSkipGramsWithOptions(words, n, k, NGramOptions{})
+}
+
+// WordsToSkipGramsWithOptions is WordsToSkipGrams with options, a skip-gram
+// must not start or end with a stop word and must not cross a boundary
+func WordsToSkipGramsWithOptions(words []string, n int, k int, opts NGramOptions) map[string]int {
+	result := make(map[string]int, len(words))
+	if n <= 0 || k < 0 {
+		return result
+	}
+	phrases := opts.phrases(len(words))
+	positions := make([]int, n) // word indexes of the current skip-gram
+	selected := make([]string, n)
+	var extend func(depth int, skipped int)
+	extend = func(depth int, skipped int) {
+		if depth == n {
+			if !opts.allowed(words, phrases, positions[0], positions[n-1]) {
+				return
+			}
+			for j, pos := range positions {
+				selected[j] = words[pos]
+			}
+			result[strings.Join(selected, " ")] += 1
+			return
+		}
+		prev := positions[depth-1]
+		for skip := 0; skip+skipped <= k && prev+1+skip < len(words); skip++ {
+			positions[depth] = prev + 1 + skip
+			extend(depth+1, skipped+skip)
+		}
+	}
+	for i := range words {
+		positions[0] = i
+		extend(1, 0)
+	}
+	return result
+}
+
 // There are often several ways to represent the same string. For example,
 // an "é" can be represented in a string as a single rune ("\u00e9")
 // or an "e" followed by an acute accent ("e\u0301").
 // They should be treated as equal in text processing.
 // Vietnamese text has an extra problem: diacritic position,
-// example: old style: òa, óa, ỏa, õa, ọa; new style: oà, oá, oả, oã, oạ
+// example: old style: òa, óa, ỏa, õa, ọa; new style: oà, oá, oả, oã, oạ.
+// NormalizeText converts Vietnamese syllables to the old style.
 func NormalizeText(text string) string {
 	transformer := transform.Chain(norm.NFKD, norm.NFKC)
 	ret, _, _ := transform.String(transformer, text)
-	// TODO: Vietnamese diacritic position
+	ret = NormalizeVietnameseTone(ret, ToneStyleOld)
 	return ret
 }
 
@@ -212,12 +437,6 @@ func removeVietnamDiacritic(char rune) rune {
 
 // example: Đào => Dao
 func RemoveVietnamDiacritic(text string) string {
-	transformer := transform.Chain(
-		norm.NFKD, runes.Remove(runes.In(unicode.Mn)), norm.NFKC)
-	text, _, _ = transform.String(transformer, text)
-	chars := make([]rune, 0)
-	for _, r := range []rune(text) {
-		chars = append(chars, removeVietnamDiacritic(r))
-	}
-	return string(chars)
+	text, _, _ = transform.String(NewVietnamDiacriticRemover(), text)
+	return text
 }
@@ -37,6 +37,79 @@ Trực tiếp ngay bây giờ trên http://www.gametv1.vn. ______ Ahihi`
 	}
 }
 
+func TestTextToWordsWithBoundaries(t *testing.T) {
+	words, boundaries := TextToWordsWithBoundaries(
+		"Hi, Mr. Ann. \"Bye\"; ok\nbob ... ok (TP.HCM 2.0)! Go")
+	if r, e := fmt.Sprint(words), "[Hi Mr Ann Bye ok bob ok TP.HCM 2.0 Go]"; r != e {
+		t.Errorf("error TextToWordsWithBoundaries words: real: %v, expected: %v", r, e)
+	}
+	if r, e := fmt.Sprint(boundaries), "[3 5 9]"; r != e {
+		t.Errorf("error TextToWordsWithBoundaries boundaries: real: %v, expected: %v", r, e)
+	}
+	for _, para := range paragraphs {
+		words, _ := TextToWordsWithBoundaries(para)
+		if r, e := fmt.Sprint(words), fmt.Sprint(TextToWords(para)); r != e {
+			t.Errorf("error TextToWordsWithBoundaries: real: %v, expected: %v", r, e)
+		}
+	}
+}
+
+func TestWordsToNGramRange(t *testing.T) {
+	words := []string{"a", "b", "c", "a", "b"}
+	r := WordsToNGramRange(words, 1, 2, NGramOptions{})
+	e := map[string]int{"a": 2, "b": 2, "c": 1, "a b": 2, "b c": 1, "c a": 1}
+	if fmt.Sprint(r) != fmt.Sprint(e) {
+		t.Errorf("error WordsToNGramRange: real: %v, expected: %v", r, e)
+	}
+	for n := 1; n <= 3; n++ { // same as calling WordsToNGrams for each n
+		r := WordsToNGramRange(words, n, n, NGramOptions{})
+		if e := WordsToNGrams(words, n); fmt.Sprint(r) != fmt.Sprint(e) {
+			t.Errorf("error WordsToNGramRange n=%v: real: %v, expected: %v", n, r, e)
+		}
+	}
+	r = WordsToNGramRange(words, 2, 3, NGramOptions{Boundaries: []int{3}})
+	e = map[string]int{"a b": 2, "b c": 1, "a b c": 1}
+	if fmt.Sprint(r) != fmt.Sprint(e) {
+		t.Errorf("error WordsToNGramRange Boundaries: real: %v, expected: %v", r, e)
+	}
+	r = TextToNGramRange("Xin chào. Hà Nội\nSài Gòn", 2, 3, NGramOptions{StopAtBoundaries: true})
+	e = map[string]int{"xin chào": 1, "hà nội": 1, "sài gòn": 1}
+	if fmt.Sprint(r) != fmt.Sprint(e) {
+		t.Errorf("error TextToNGramRange StopAtBoundaries: real: %v, expected: %v", r, e)
+	}
+	r = TextToNGramsWithOptions("Xin chào, Hà Nội", 2, NGramOptions{StopAtBoundaries: true})
+	if len(r) != 3 { // a comma is not a sentence boundary
+		t.Errorf("error TextToNGramsWithOptions StopAtBoundaries: %v", r)
+	}
+	r = TextToNGramRange("ông Mr. Smith đến TP. Hồ Chí Minh", 2, 2, NGramOptions{StopAtBoundaries: true})
+	if !(r["mr smith"] == 1 && r["tp hồ"] == 1 && len(r) == 7) { // one sentence
+		t.Errorf("error TextToNGramRange abbreviations: %v", r)
+	}
+}
+
+func TestWordsToSkipGrams(t *testing.T) {
+	words := []string{"a", "b", "c", "d"}
+	r := WordsToSkipGrams(words, 2, 1)
+	e := map[string]int{"a b": 1, "b c": 1, "c d": 1, "a c": 1, "b d": 1}
+	if fmt.Sprint(r) != fmt.Sprint(e) {
+		t.Errorf("error WordsToSkipGrams: real: %v, expected: %v", r, e)
+	}
+	r = WordsToSkipGrams(words, 3, 1)
+	e = map[string]int{"a b c": 1, "b c d": 1, "a b d": 1, "a c d": 1}
+	if fmt.Sprint(r) != fmt.Sprint(e) {
+		t.Errorf("error WordsToSkipGrams n=3: real: %v, expected: %v", r, e)
+	}
+	if r, e := WordsToSkipGrams(words, 2, 0), WordsToNGrams(words, 2); fmt.Sprint(r) != fmt.Sprint(e) {
+		t.Errorf("error WordsToSkipGrams k=0: real: %v, expected: %v", r, e)
+	}
+	r = WordsToSkipGramsWithOptions(words, 2, 2,
+		NGramOptions{Boundaries: []int{3}, StopWords: NewStopWords("b")})
+	e = map[string]int{"a c": 1}
+	if fmt.Sprint(r) != fmt.Sprint(e) {
+		t.Errorf("error WordsToSkipGramsWithOptions: real: %v, expected: %v", r, e)
+	}
+}
+
 func TestHashTextToInt64(t *testing.T) {
 	nWords := 1000000 // fast
 	words := make(map[string]bool)
@@ -0,0 +1,173 @@
+package textproc
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ScoredTerm is a term (n-gram) with its score
+type ScoredTerm struct {
+	Term  string
+	Score float64
+}
+
+// Corpus tracks document frequency of n-grams (n from 1 to MaxN),
+// it is used for ranking keywords by TF-IDF.
+// Corpus is safe for concurrent use.
+type Corpus struct {
+	maxN    int
+	mu      sync.RWMutex
+	nDocs   int
+	docFreq map[string]int // number of documents that contain the term
+}
+
+// NewCorpus returns an empty corpus that tracks n-grams with n from 1 to maxN
+func NewCorpus(maxN int) *Corpus {
+	if maxN < 1 {
+		maxN = 1
+	}
+	return &Corpus{maxN: maxN, docFreq: make(map[string]int)}
+}
+
+// MaxN returns the maxN passed to NewCorpus
+func (c *Corpus) MaxN() int { return c.maxN }
+
+// NumDocs returns number of documents added to the corpus
+func (c *Corpus) NumDocs() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.nDocs
+}
+
+// DocFreq returns number of documents that contain the term, the term is
+// normalized in the same way as documents (NormalizeText, lowercase,
+// punctuations removed), example: "Hoà Bình" and "hòa bình" are one term
+func (c *Corpus) DocFreq(term string) int {
+	term = strings.Join(TextToWords(strings.ToLower(NormalizeText(term))), " ")
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.docFreq[term]
+}
+
+// termFreqs returns count of n-grams in the doc for n from 1 to maxN
+func (c *Corpus) termFreqs(doc string) map[string]int {
+	doc = NormalizeText(doc)
+	ret := make(map[string]int)
+	for n := 1; n <= c.maxN; n++ {
+		for term, count := range TextToNGrams(doc, n) {
+			ret[term] += count
+		}
+	}
+	return ret
+}
+
+// AddDocument updates document frequency of n-grams in the doc
+func (c *Corpus) AddDocument(doc string) {
+	terms := c.termFreqs(doc)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nDocs++
+	for term := range terms {
+		c.docFreq[term]++
+	}
+}
+
+// idf must be called while holding the lock,
+// smoothed: idf = ln((1 + nDocs) / (1 + docFreq)) + 1
+func (c *Corpus) idf(term string) float64 {
+	return math.Log(float64(1+c.nDocs)/float64(1+c.docFreq[term])) + 1
+}
+
+// Keywords returns topK terms of the doc that have the highest TF-IDF,
+// the doc does not need to be added to the corpus. Returns all terms
+// if topK <= 0.
+func (c *Corpus) Keywords(doc string, topK int) []ScoredTerm {
+	terms := c.termFreqs(doc)
+	ret := make([]ScoredTerm, 0, len(terms))
+	c.mu.RLock()
+	for term, count := range terms {
+		ret = append(ret, ScoredTerm{Term: term, Score: float64(count) * c.idf(term)})
+	}
+	c.mu.RUnlock()
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Score != ret[j].Score {
+			return ret[i].Score > ret[j].Score
+		}
+		return ret[i].Term < ret[j].Term
+	})
+	if topK > 0 && len(ret) > topK {
+		ret = ret[:topK]
+	}
+	return ret
+}
+
+// corpusData is the saved form of Corpus
+type corpusData struct {
+	MaxN    int
+	NumDocs int
+	DocFreq map[string]int
+}
+
+// Save writes the corpus stats to the writer, the corpus can be read
+// by LoadCorpus
+func (c *Corpus) Save(w io.Writer) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	data := corpusData{MaxN: c.maxN, NumDocs: c.nDocs, DocFreq: c.docFreq}
+	if err := gob.NewEncoder(w).Encode(data); err != nil {
+		return fmt.Errorf("error gob Encode: %v", err)
+	}
+	return nil
+}
+
+// LoadCorpus reads a corpus that was written by Corpus_Save
+func LoadCorpus(r io.Reader) (*Corpus, error) {
+	var data corpusData
+	if err := gob.NewDecoder(r).Decode(&data); err != nil {
+		return nil, fmt.Errorf("error gob Decode: %v", err)
+	}
+	c := NewCorpus(data.MaxN)
+	c.nDocs = data.NumDocs
+	if data.DocFreq != nil {
+		c.docFreq = data.DocFreq
+	}
+	return c, nil
+}
+
+// SaveFile writes the corpus stats to a file, the file is replaced
+// atomically so a crash while saving does not corrupt the old file
+func (c *Corpus) SaveFile(path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("error CreateTemp: %v", err)
+	}
+	defer os.Remove(tmp.Name()) // no effect after a successful rename
+	if err := c.Save(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error Close: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error Rename: %v", err)
+	}
+	return nil
+}
+
+// LoadCorpusFile reads a corpus that was written by Corpus_SaveFile
+func LoadCorpusFile(path string) (*Corpus, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error Open: %v", err)
+	}
+	defer f.Close()
+	return LoadCorpus(f)
+}
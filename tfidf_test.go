@@ -0,0 +1,51 @@
+package textproc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCorpus(t *testing.T) {
+	corpus := NewCorpus(2)
+	for _, para := range paragraphs {
+		corpus.AddDocument(para)
+	}
+	if corpus.NumDocs() != len(paragraphs) || corpus.DocFreq("the") != 3 {
+		t.Errorf("error Corpus: nDocs: %v, docFreq: %v",
+			corpus.NumDocs(), corpus.DocFreq("the"))
+	}
+
+	toneCorpus := NewCorpus(2)
+	toneCorpus.AddDocument("Hoà bình là khát vọng.")
+	toneCorpus.AddDocument("Tin hòa bình, thời sự")
+	for _, term := range []string{"hoà bình", "hòa bình", "Hoà  Bình!"} {
+		if r := toneCorpus.DocFreq(term); r != 2 {
+			t.Errorf("error Corpus DocFreq %q: real: %v, expected: %v", term, r, 2)
+		}
+	}
+
+	keywords := corpus.Keywords(`Sodium, atomic number 11, was first isolated by
+Peter Dager in 1807. Sodium is named Na, sodium is salt.`, 3)
+	if len(keywords) != 3 || keywords[0].Term != "sodium" ||
+		keywords[0].Score <= keywords[1].Score {
+		t.Errorf("error Corpus Keywords: %+v", keywords)
+	}
+
+	path := filepath.Join(t.TempDir(), "corpus.gob")
+	if err := corpus.SaveFile(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadCorpusFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.NumDocs() != corpus.NumDocs() || loaded.MaxN() != 2 ||
+		loaded.DocFreq("the") != 3 || loaded.DocFreq("sẽ thêm") != 1 {
+		t.Errorf("error LoadCorpusFile")
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("error SaveFile: temporary file is not removed")
+	}
+}
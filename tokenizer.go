@@ -0,0 +1,282 @@
+package textproc
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenKind is the type of a Token
+type TokenKind string
+
+// Token kinds
+const (
+	TokenWord        TokenKind = "word"
+	TokenNumber      TokenKind = "number" // example: "2020", "2.0", "1,000"
+	TokenURL         TokenKind = "url"
+	TokenEmail       TokenKind = "email"
+	TokenHashtag     TokenKind = "hashtag" // example: "#golang"
+	TokenMention     TokenKind = "mention" // example: "@mywrap"
+	TokenEmoji       TokenKind = "emoji"   // an emoji with its modifiers and ZWJ sequence
+	TokenPunctuation TokenKind = "punctuation"
+)
+
+// Token is a result of Tokenizer_Tokenize, Start and End are byte offsets
+// of the token in the text (text[Start:End] == Text)
+type Token struct {
+	Text  string
+	Start int
+	End   int
+	Kind  TokenKind
+}
+
+// Tokenizer splits a text to typed tokens. The zero value only emits words
+// and numbers, NewTokenizer returns a Tokenizer that detects all kinds.
+type Tokenizer struct {
+	// IsWordChar returns true for chars of words and numbers,
+	// nil means unicode letters, digits and marks
+	IsWordChar func(rune) bool
+	// DetectURLs emits URLs (http, https, ftp, www.) as one token instead
+	// of words and punctuations, trailing punctuations are not part of URLs
+	DetectURLs     bool
+	DetectEmails   bool
+	DetectHashtags bool
+	DetectMentions bool
+	// DetectEmoji emits emoji tokens, emoji are punctuations if it is false
+	DetectEmoji bool
+	// KeepPunctuation emits punctuation tokens (a run of the same char is
+	// one token, example: "..."), punctuations are dropped if it is false
+	KeepPunctuation bool
+}
+
+// NewTokenizer returns a Tokenizer that detects all token kinds
+func NewTokenizer() *Tokenizer {
+	return &Tokenizer{
+		DetectURLs:      true,
+		DetectEmails:    true,
+		DetectHashtags:  true,
+		DetectMentions:  true,
+		DetectEmoji:     true,
+		KeepPunctuation: true,
+	}
+}
+
+// Tokenize splits a text to tokens with NewTokenizer()
+func Tokenize(text string) []Token {
+	return NewTokenizer().Tokenize(text)
+}
+
+var (
+	tokenURLRegexp     = regexp.MustCompile(`^(?i)(?:https?://|ftp://|www\.)[^\s<>"“”]+`)
+	tokenEmailRegexp   = regexp.MustCompile(`^[\p{L}\p{N}._%+-]+@[\p{L}\p{N}-]+(?:\.[\p{L}\p{N}-]+)+`)
+	tokenHashtagRegexp = regexp.MustCompile(`^#[\p{L}\p{M}\p{N}_]+`)
+	tokenMentionRegexp = regexp.MustCompile(`^@[\p{L}\p{M}\p{N}_](?:[\p{L}\p{M}\p{N}_.]*[\p{L}\p{M}\p{N}_])?`)
+)
+
+// Tokenize splits the text to tokens, spaces are never part of a token.
+// A word can contain inner ".", "-", "'", "_" (example: "TP.HCM",
+// "e-mail", "can't"), a number can contain inner ".", ",", ":" (example:
+// "1,000.5", "10:30").
+func (t *Tokenizer) Tokenize(text string) []Token {
+	isWordChar := t.IsWordChar
+	if isWordChar == nil {
+		isWordChar = isUnicodeWordChar
+	}
+	ret := make([]Token, 0)
+	var emails emailScanner
+	emit := func(start int, end int, kind TokenKind) {
+		ret = append(ret, Token{Text: text[start:end], Start: start, End: end, Kind: kind})
+	}
+	for i := 0; i < len(text); {
+		char, size := utf8.DecodeRuneInString(text[i:])
+		if unicode.IsSpace(char) {
+			i += size
+			continue
+		}
+		prev, _ := utf8.DecodeLastRuneInString(text[:i])
+		atBoundary := i == 0 || !isWordChar(prev)
+		if atBoundary {
+			if end, kind := t.matchSpecial(text, i, char, &emails); end > i {
+				emit(i, end, kind)
+				i = end
+				continue
+			}
+		}
+		switch {
+		case t.DetectEmoji && isEmojiRune(char):
+			end := scanEmoji(text, i)
+			emit(i, end, TokenEmoji)
+			i = end
+		case isWordChar(char):
+			end, isNumber := scanWord(text, i, isWordChar)
+			kind := TokenWord
+			if isNumber {
+				kind = TokenNumber
+			}
+			emit(i, end, kind)
+			i = end
+		default:
+			end := i + size
+			for end < len(text) && strings.HasPrefix(text[end:], string(char)) {
+				end += size
+			}
+			if t.KeepPunctuation {
+				emit(i, end, TokenPunctuation)
+			}
+			i = end
+		}
+	}
+	return ret
+}
+
+// matchSpecial returns end and kind of the URL, email, hashtag or mention
+// that starts at i, returns i if there is no match
+func (t *Tokenizer) matchSpecial(text string, i int, char rune, emails *emailScanner) (int, TokenKind) {
+	rest := text[i:]
+	if t.DetectURLs && strings.ContainsRune("hHfFwW", char) {
+		if loc := tokenURLRegexp.FindStringIndex(rest); loc != nil {
+			return i + len(trimURLPunctuation(rest[:loc[1]])), TokenURL
+		}
+	}
+	if t.DetectEmails && char != '#' && char != '@' && emails.atFollows(text, i) {
+		if loc := tokenEmailRegexp.FindStringIndex(rest); loc != nil {
+			return i + loc[1], TokenEmail
+		}
+	}
+	if t.DetectHashtags && char == '#' {
+		if loc := tokenHashtagRegexp.FindStringIndex(rest); loc != nil {
+			return i + loc[1], TokenHashtag
+		}
+	}
+	if t.DetectMentions && char == '@' {
+		if loc := tokenMentionRegexp.FindStringIndex(rest); loc != nil {
+			return i + loc[1], TokenMention
+		}
+	}
+	return i, ""
+}
+
+// emailScanner finds the end of runs of email local part chars, so the
+// email regexp only runs if an "@" follows and each char is scanned once
+// (running the regexp at every token start is quadratic, example: "a%a%a%")
+type emailScanner struct {
+	end int // end of the last scanned run
+}
+
+// atFollows returns true if the run of local part chars that contains i is
+// followed by an "@", i must not decrease between calls
+func (s *emailScanner) atFollows(text string, i int) bool {
+	if i >= s.end {
+		s.end = i
+		for s.end < len(text) {
+			char, size := utf8.DecodeRuneInString(text[s.end:])
+			if !isEmailLocalChar(char) {
+				break
+			}
+			s.end += size
+		}
+	}
+	return s.end > i && strings.HasPrefix(text[s.end:], "@")
+}
+
+// isEmailLocalChar returns true for chars of the tokenEmailRegexp local part
+func isEmailLocalChar(char rune) bool {
+	return unicode.IsLetter(char) || unicode.IsNumber(char) ||
+		strings.ContainsRune("._%+-", char)
+}
+
+// trimURLPunctuation removes trailing punctuations of an URL in a text,
+// a closing bracket is kept if the URL contains the opening bracket,
+// example: "http://a.vn/x)." in "(see http://a.vn/x)."
+func trimURLPunctuation(u string) string {
+	for len(u) > 0 {
+		last, size := utf8.DecodeLastRuneInString(u)
+		switch {
+		case strings.ContainsRune(".,;:!?'\"’”…", last):
+		case last == ')' && strings.Count(u, "(") < strings.Count(u, ")"):
+		case last == ']' && strings.Count(u, "[") < strings.Count(u, "]"):
+		default:
+			return u
+		}
+		u = u[:len(u)-size]
+	}
+	return u
+}
+
+func isUnicodeWordChar(char rune) bool {
+	return unicode.IsLetter(char) || unicode.IsDigit(char) || unicode.IsMark(char)
+}
+
+// scanWord returns end of the word that starts at i, and whether the word
+// is a number
+func scanWord(text string, i int, isWordChar func(rune) bool) (end int, isNumber bool) {
+	end = i
+	isNumber = true
+	for end < len(text) {
+		char, size := utf8.DecodeRuneInString(text[end:])
+		if isWordChar(char) {
+			if !unicode.IsDigit(char) {
+				isNumber = false
+			}
+			end += size
+			continue
+		}
+		// an inner connector must be followed by a word char
+		next, nextSize := utf8.DecodeRuneInString(text[end+size:])
+		if nextSize == 0 || !isWordChar(next) {
+			break
+		}
+		prev, _ := utf8.DecodeLastRuneInString(text[:end])
+		isConnector := false
+		switch char {
+		case '.':
+			isConnector = true
+		case '-', '\'', '’', '_':
+			isConnector = true
+			isNumber = false
+		case ',', ':':
+			isConnector = unicode.IsDigit(prev) && unicode.IsDigit(next)
+		}
+		if !isConnector {
+			break
+		}
+		end += size
+	}
+	return end, isNumber
+}
+
+// isEmojiRune returns true for chars in the main emoji blocks
+func isEmojiRune(char rune) bool {
+	return 0x1F000 <= char && char <= 0x1FAFF || // emoticons, symbols, flags
+		0x2600 <= char && char <= 0x27BF || // misc symbols, dingbats
+		0x2300 <= char && char <= 0x23FF || // misc technical: ⌚ ⏰
+		0x2B00 <= char && char <= 0x2BFF // arrows: ⭐ ⬛
+}
+
+// scanEmoji returns end of the emoji sequence that starts at i: modifiers
+// (variation selector, skin tone, keycap), ZWJ sequences and flags
+func scanEmoji(text string, i int) int {
+	first, end := utf8.DecodeRuneInString(text[i:])
+	end += i
+	isFlag := 0x1F1E6 <= first && first <= 0x1F1FF
+	for end < len(text) {
+		char, size := utf8.DecodeRuneInString(text[end:])
+		switch {
+		case char == 0xFE0F || char == 0x20E3 || 0x1F3FB <= char && char <= 0x1F3FF:
+			end += size
+		case char == 0x200D: // zero width joiner
+			next, nextSize := utf8.DecodeRuneInString(text[end+size:])
+			if !isEmojiRune(next) {
+				return end
+			}
+			end += size + nextSize
+		case isFlag && 0x1F1E6 <= char && char <= 0x1F1FF:
+			end += size
+			isFlag = false
+		default:
+			return end
+		}
+	}
+	return end
+}
@@ -0,0 +1,77 @@
+package textproc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenize(t *testing.T) {
+	text := `Xem "Sẻ đệ" (NDB 2.0) tại http://www.gametv1.vn/a_(b). ` +
+		`Liên hệ admin@gametv1.vn, #LMHT @Sơ_Luyến 👍🏽👨‍👩‍👧🇻🇳... TP.HCM 1,000.5 can't`
+	tokens := Tokenize(text)
+	for _, token := range tokens {
+		if text[token.Start:token.End] != token.Text {
+			t.Errorf("error Tokenize offsets: %+v", token)
+		}
+	}
+	type tk struct {
+		Text string
+		Kind TokenKind
+	}
+	real := make([]tk, 0, len(tokens))
+	for _, token := range tokens {
+		real = append(real, tk{token.Text, token.Kind})
+	}
+	expected := []tk{
+		{"Xem", TokenWord}, {`"`, TokenPunctuation}, {"Sẻ", TokenWord},
+		{"đệ", TokenWord}, {`"`, TokenPunctuation}, {"(", TokenPunctuation},
+		{"NDB", TokenWord}, {"2.0", TokenNumber}, {")", TokenPunctuation},
+		{"tại", TokenWord}, {"http://www.gametv1.vn/a_(b)", TokenURL},
+		{".", TokenPunctuation}, {"Liên", TokenWord}, {"hệ", TokenWord},
+		{"admin@gametv1.vn", TokenEmail}, {",", TokenPunctuation},
+		{"#LMHT", TokenHashtag}, {"@Sơ_Luyến", TokenMention},
+		{"👍🏽", TokenEmoji}, {"👨‍👩‍👧", TokenEmoji}, {"🇻🇳", TokenEmoji},
+		{"...", TokenPunctuation}, {"TP.HCM", TokenWord},
+		{"1,000.5", TokenNumber}, {"can't", TokenWord},
+	}
+	if !reflect.DeepEqual(real, expected) {
+		t.Errorf("error Tokenize:\nreal:     %v\nexpected: %v", real, expected)
+	}
+}
+
+func TestTokenizerOptions(t *testing.T) {
+	var tokenizer Tokenizer // zero value: only words and numbers
+	var real []string
+	for _, token := range tokenizer.Tokenize("Go 1.23, see golang.org #go @gopher 🙂") {
+		real = append(real, token.Text)
+	}
+	expected := []string{"Go", "1.23", "see", "golang.org", "go", "gopher"}
+	if !reflect.DeepEqual(real, expected) {
+		t.Errorf("error Tokenizer zero value: real: %v, expected: %v", real, expected)
+	}
+
+	tokenizer.IsWordChar = func(r rune) bool { return AlphaNumeric[r] }
+	tokens := tokenizer.Tokenize("ab日本cd")
+	if len(tokens) != 2 || tokens[0].Text != "ab" || tokens[1].Start != 8 {
+		t.Errorf("error Tokenizer IsWordChar: %+v", tokens)
+	}
+}
+
+func TestTokenizeLongPunctuatedText(t *testing.T) {
+	// a "%" does not break an email local part, the email regexp must not
+	// run at every token start
+	for _, text := range []string{strings.Repeat("a%", 50000),
+		strings.Repeat("a%", 50000) + "@mail.com", strings.Repeat("a@", 50000)} {
+		beginT := time.Now()
+		tokens := Tokenize(text)
+		if dur := time.Since(beginT); dur > time.Second {
+			t.Errorf("error Tokenize is too slow: %v, %v tokens", dur, len(tokens))
+		}
+	}
+	tokens := Tokenize("x%y " + strings.Repeat("a%", 3) + "b@mail.com")
+	if last := tokens[len(tokens)-1]; last.Kind != TokenEmail || last.Text != "a%a%a%b@mail.com" {
+		t.Errorf("error Tokenize email after a long run: %+v", tokens)
+	}
+}
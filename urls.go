@@ -0,0 +1,163 @@
+package textproc
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// DefaultTrackingParams are query parameters that do not change the content
+// of a page, a name that ends with "*" is a prefix
+var DefaultTrackingParams = []string{
+	"utm_*", "fbclid", "gclid", "dclid", "gclsrc", "msclkid", "yclid",
+	"mc_cid", "mc_eid", "_ga", "igshid", "zarsrc",
+}
+
+// CanonicalizeOptions configures CanonicalizeURL
+type CanonicalizeOptions struct {
+	// TrackingParams are query parameters to remove, a name that ends with
+	// "*" is a prefix, example: "utm_*". Names are case insensitive.
+	// Nil means DefaultTrackingParams, use an empty slice to keep all params.
+	TrackingParams []string
+	// IgnoreScheme treats http and https as the same: http URLs are
+	// converted to https
+	IgnoreScheme bool
+}
+
+// CanonicalizeURL returns a canonical form of an absolute URL, so different
+// forms of the same page are treated as one URL:
+// scheme and host are lowercased, IDN host is converted to punycode,
+// default port is removed, dot segments in path are removed, empty path is
+// converted to "/", query parameters are sorted and tracking parameters are
+// removed, fragment is removed.
+// Example: "HTTP://Example.com:80/a/../b?z=1&utm_source=fb&a=2#top" =>
+// "http://example.com/b?a=2&z=1"
+func CanonicalizeURL(u string, opts CanonicalizeOptions) (string, error) {
+	parsed, err := url.Parse(strings.TrimSpace(u))
+	if err != nil {
+		return "", fmt.Errorf("error url Parse: %v", err)
+	}
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return "", fmt.Errorf("error not an absolute URL: %v", u)
+	}
+	canonicalizeURL(parsed, opts)
+	return parsed.String(), nil
+}
+
+// canonicalizeURL modifies the absolute URL in place, see CanonicalizeURL
+func canonicalizeURL(u *url.URL, opts CanonicalizeOptions) {
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Fragment, u.RawFragment = "", ""
+
+	host, port := u.Hostname(), u.Port()
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if ascii, err := idna.Lookup.ToASCII(host); err == nil {
+		host = ascii
+	}
+	if port == "80" && u.Scheme == "http" || port == "443" && u.Scheme == "https" {
+		port = ""
+	}
+	// the default port is removed based on the original scheme
+	if opts.IgnoreScheme && u.Scheme == "http" {
+		u.Scheme = "https"
+	}
+	switch {
+	case port != "":
+		u.Host = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"): // IPv6
+		u.Host = "[" + host + "]"
+	default:
+		u.Host = host
+	}
+
+	escapedPath := removeDotSegments(u.EscapedPath())
+	if escapedPath == "" {
+		escapedPath = "/"
+	}
+	if path, err := url.PathUnescape(escapedPath); err == nil {
+		u.Path, u.RawPath = path, escapedPath
+	}
+
+	u.RawQuery = canonicalizeQuery(u.RawQuery, opts.TrackingParams)
+	u.ForceQuery = false
+}
+
+// canonicalizeQuery removes tracking params from the raw query and sorts
+// params by name (params that have the same name keep their order)
+func canonicalizeQuery(rawQuery string, trackingParams []string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	if trackingParams == nil {
+		trackingParams = DefaultTrackingParams
+	}
+	type param struct{ name, raw string }
+	params := make([]param, 0)
+	for _, raw := range strings.FieldsFunc(rawQuery, func(r rune) bool { return r == '&' || r == ';' }) {
+		name := raw
+		if i := strings.Index(raw, "="); i != -1 {
+			name = raw[:i]
+		}
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		if isTrackingParam(name, trackingParams) {
+			continue
+		}
+		params = append(params, param{name: name, raw: raw})
+	}
+	sort.SliceStable(params, func(i, j int) bool { return params[i].name < params[j].name })
+	raws := make([]string, len(params))
+	for i, p := range params {
+		raws[i] = p.raw
+	}
+	return strings.Join(raws, "&")
+}
+
+func isTrackingParam(name string, trackingParams []string) bool {
+	name = strings.ToLower(name)
+	for _, t := range trackingParams {
+		t = strings.ToLower(t)
+		if prefix, isPrefix := strings.CutSuffix(t, "*"); isPrefix {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if name == t {
+			return true
+		}
+	}
+	return false
+}
+
+// removeDotSegments removes "." and ".." segments of a path,
+// RFC 3986 section 5.2.4, example: "/a/b/../c/./d" => "/a/c/d"
+func removeDotSegments(path string) string {
+	if !strings.Contains(path, ".") {
+		return path
+	}
+	segments := strings.Split(path, "/")
+	ret := make([]string, 0, len(segments))
+	for i, s := range segments {
+		isLast := i == len(segments)-1
+		switch s {
+		case ".":
+			if isLast {
+				ret = append(ret, "")
+			}
+		case "..":
+			if len(ret) > 1 || len(ret) == 1 && ret[0] != "" {
+				ret = ret[:len(ret)-1]
+			}
+			if isLast {
+				ret = append(ret, "")
+			}
+		default:
+			ret = append(ret, s)
+		}
+	}
+	return strings.Join(ret, "/")
+}
@@ -0,0 +1,68 @@
+package textproc
+
+import (
+	"testing"
+)
+
+func TestCanonicalizeURL(t *testing.T) {
+	for i, c := range []struct {
+		url      string
+		opts     CanonicalizeOptions
+		expected string
+	}{
+		{"HTTP://Example.COM:80/a/../b/./c?z=1&utm_source=fb&a=2#top", CanonicalizeOptions{},
+			"http://example.com/b/c?a=2&z=1"},
+		{"https://example.com:443", CanonicalizeOptions{}, "https://example.com/"},
+		{"https://example.com:8443/x?", CanonicalizeOptions{}, "https://example.com:8443/x"},
+		{"http://example.com/?fbclid=abc&gclid=x&UTM_Medium=y", CanonicalizeOptions{},
+			"http://example.com/"},
+		{"http://example.com/?b=2&a=3&b=1", CanonicalizeOptions{}, "http://example.com/?a=3&b=2&b=1"},
+		{"http://example.com/?ref=home&id=1", CanonicalizeOptions{TrackingParams: []string{"ref"}},
+			"http://example.com/?id=1"},
+		{"http://example.com/?utm_source=fb", CanonicalizeOptions{TrackingParams: []string{}},
+			"http://example.com/?utm_source=fb"},
+		{"http://example.com/a", CanonicalizeOptions{IgnoreScheme: true}, "https://example.com/a"},
+		{"http://example.com:80/a", CanonicalizeOptions{IgnoreScheme: true}, "https://example.com/a"},
+		{"http://Bücher.example/tin-tức", CanonicalizeOptions{},
+			"http://xn--bcher-kva.example/tin-t%E1%BB%A9c"},
+		{"http://[::1]:80/../a%2Fb", CanonicalizeOptions{}, "http://[::1]/a%2Fb"},
+	} {
+		r, err := CanonicalizeURL(c.url, c.opts)
+		if err != nil || r != c.expected {
+			t.Errorf("error CanonicalizeURL %v: real: %v, %v, expected: %v", i, r, err, c.expected)
+		}
+	}
+	if _, err := CanonicalizeURL("/relative/path", CanonicalizeOptions{}); err == nil {
+		t.Errorf("error CanonicalizeURL relative URL: expected an error")
+	}
+}
+
+func TestRemoveDotSegments(t *testing.T) {
+	for in, e := range map[string]string{
+		"/a/b/c/./../../g": "/a/g",
+		"/a/b/..":          "/a/",
+		"/..":              "/",
+		"/a/./":            "/a/",
+		"/a.html":          "/a.html",
+	} {
+		if r := removeDotSegments(in); r != e {
+			t.Errorf("error removeDotSegments %v: real: %v, expected: %v", in, r, e)
+		}
+	}
+}
+
+func TestHTMLGetHREFsCanonicalize(t *testing.T) {
+	root := HTMLParseToNode(`<a href="/news/1?utm_source=fb">1</a>
+<a href="HTTPS://Example.com/news/1#comments">1</a>
+<a href="mailto:a@example.com">mail</a>`)
+	opts := LinkOptions{Canonicalize: &CanonicalizeOptions{IgnoreScheme: true}}
+	urls := HTMLGetHREFsWithOptions("http://example.com/", root, opts)
+	if len(urls) != 2 || urls[0] != "https://example.com/news/1" ||
+		urls[1] != "mailto:a@example.com" {
+		t.Errorf("error HTMLGetHREFsWithOptions: %v", urls)
+	}
+	links := HTMLGetLinksWithOptions("http://example.com/", root, opts)
+	if len(links) != 3 || links[0].URL != links[1].URL || !links[0].Internal {
+		t.Errorf("error HTMLGetLinksWithOptions: %+v", links)
+	}
+}
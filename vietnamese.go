@@ -0,0 +1,449 @@
+package textproc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Tone is one of the 6 Vietnamese tones (thanh điệu)
+type Tone int
+
+// Vietnamese tones, a Tone is also the index of a toned vowel in
+// vowelWithTones
+const (
+	ToneNone  Tone = iota // thanh ngang, example: a
+	ToneGrave             // thanh huyền, example: à
+	ToneAcute             // thanh sắc, example: á
+	ToneHook              // thanh hỏi, example: ả
+	ToneTilde             // thanh ngã, example: ã
+	ToneDot               // thanh nặng, example: ạ
+)
+
+// ToneStyle decides where to put the tone mark in a syllable that has
+// vowel cluster "oa", "oe" or "uy" without a final consonant
+type ToneStyle int
+
+// Vietnamese tone placement styles
+const (
+	// ToneStyleOld puts the tone mark on the first vowel: hòa, khỏe, thủy.
+	// This is the style that most Vietnamese news sites use.
+	ToneStyleOld ToneStyle = iota
+	// ToneStyleNew puts the tone mark on the main vowel: hoà, khoẻ, thuỷ.
+	ToneStyleNew
+)
+
+// toneMarks maps the combining marks of tones (in NFD form) to tones
+var toneMarks = map[rune]Tone{
+	'\u0300': ToneGrave, '\u0301': ToneAcute, '\u0309': ToneHook,
+	'\u0303': ToneTilde, '\u0323': ToneDot,
+}
+
+// vowelTone describes a Vietnamese vowel rune as a toneless vowel and a tone
+type vowelTone struct {
+	base rune
+	tone Tone
+}
+
+var (
+	// vowelToTone maps a (lower or upper case) Vietnamese vowel to its
+	// toneless form and its tone
+	vowelToTone = make(map[rune]vowelTone)
+	// vowelWithTones maps a toneless vowel to its 6 toned forms
+	vowelWithTones = make(map[rune][6]rune)
+)
+
+// init builds the vowel tables from the vowels in lowerAlphas (letters that
+// removeVietnamDiacritic maps to a, e, i, o, u, y), the tone of a vowel is
+// its tone mark in NFD form, example: "ắ" => "ă" + acute
+func init() {
+	for _, char := range lowerAlphas {
+		if !strings.ContainsRune("aeiouy", removeVietnamDiacritic(char)) {
+			continue
+		}
+		tone := ToneNone
+		baseMarks := make([]rune, 0)
+		for _, r := range norm.NFD.String(string(char)) {
+			if t, found := toneMarks[r]; found {
+				tone = t
+			} else {
+				baseMarks = append(baseMarks, r)
+			}
+		}
+		base := []rune(norm.NFC.String(string(baseMarks)))[0]
+		for _, pair := range [][2]rune{{char, base},
+			{unicode.ToUpper(char), unicode.ToUpper(base)}} {
+			vowelToTone[pair[0]] = vowelTone{base: pair[1], tone: tone}
+			toned := vowelWithTones[pair[1]]
+			toned[tone] = pair[0]
+			vowelWithTones[pair[1]] = toned
+		}
+	}
+}
+
+// isVietnamVowel returns true if the input is a Vietnamese vowel (with or
+// without tone mark, lower or upper case)
+func isVietnamVowel(char rune) bool {
+	_, found := vowelToTone[char]
+	return found
+}
+
+// splitVowelToTone returns the toneless form and the tone of a vowel,
+// the input is returned with ToneNone if it is not a Vietnamese vowel
+func splitVowelToTone(char rune) (rune, Tone) {
+	vt, found := vowelToTone[char]
+	if !found {
+		return char, ToneNone
+	}
+	return vt.base, vt.tone
+}
+
+// applyTone returns the vowel with the tone, the input is returned
+// unchanged if it is not a Vietnamese vowel
+func applyTone(char rune, tone Tone) rune {
+	base, _ := splitVowelToTone(char)
+	toned, found := vowelWithTones[base]
+	if !found || tone < ToneNone || tone > ToneDot {
+		return char
+	}
+	return toned[tone]
+}
+
+// NormalizeVietnameseTone moves the tone mark of every Vietnamese syllable in
+// the text to the position defined by the style,
+// example: ToneStyleOld: "hoà" => "hòa", ToneStyleNew: "hòa" => "hoà".
+// Words that are not valid Vietnamese syllables are kept unchanged.
+func NormalizeVietnameseTone(text string, style ToneStyle) string {
+	runes := []rune(text)
+	changed := false
+	for i := 0; i < len(runes); {
+		if !unicode.IsLetter(runes[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && unicode.IsLetter(runes[j]) {
+			j++
+		}
+		if normalizeSyllableTone(runes[i:j], style) {
+			changed = true
+		}
+		i = j
+	}
+	if !changed {
+		return text
+	}
+	return string(runes)
+}
+
+// normalizeSyllableTone modifies the syllable in place,
+// returns true if the syllable is changed
+func normalizeSyllableTone(syllable []rune, style ToneStyle) bool {
+	vowelBegin, vowelEnd := -1, -1
+	for i, r := range syllable {
+		if isVietnamVowel(r) {
+			if vowelBegin == -1 {
+				vowelBegin = i
+			} else if vowelEnd != i {
+				return false // vowels are not contiguous
+			}
+			vowelEnd = i + 1
+		}
+	}
+	if vowelBegin == -1 {
+		return false
+	}
+	tonePos, tone := -1, ToneNone
+	for i := vowelBegin; i < vowelEnd; i++ {
+		if _, t := splitVowelToTone(syllable[i]); t != ToneNone {
+			if tonePos != -1 {
+				return false // more than one tone mark
+			}
+			tonePos, tone = i, t
+		}
+	}
+	if tonePos == -1 || !IsValidVietnameseSyllable(string(syllable)) {
+		return false
+	}
+
+	// "u" in "qu" and "i" in "gi" belong to the initial consonant
+	initial := strings.ToLower(string(syllable[:vowelBegin]))
+	if vowelEnd-vowelBegin > 1 {
+		first, _ := splitVowelToTone(unicode.ToLower(syllable[vowelBegin]))
+		if (initial == "q" && first == 'u') || (initial == "g" && first == 'i') {
+			vowelBegin++
+		}
+	}
+	vowels := make([]rune, 0, vowelEnd-vowelBegin)
+	for _, r := range syllable[vowelBegin:vowelEnd] {
+		base, _ := splitVowelToTone(unicode.ToLower(r))
+		vowels = append(vowels, base)
+	}
+	hasFinal := vowelEnd < len(syllable)
+	target := findTonePosition(vowels, hasFinal, style)
+	if target == -1 || vowelBegin+target == tonePos {
+		return false
+	}
+	syllable[tonePos] = applyTone(syllable[tonePos], ToneNone)
+	syllable[vowelBegin+target] = applyTone(syllable[vowelBegin+target], tone)
+	return true
+}
+
+// findTonePosition returns index of the vowel that holds the tone mark,
+// input vowels are lower case and toneless, returns -1 if the vowel
+// cluster is not supported
+func findTonePosition(vowels []rune, hasFinal bool, style ToneStyle) int {
+	// a vowel with a diacritic always holds the tone mark,
+	// "ư" is the last choice because of "ươ" (example: "người")
+	for i, v := range vowels {
+		switch v {
+		case 'ă', 'â', 'ê', 'ô', 'ơ':
+			return i
+		}
+	}
+	for i, v := range vowels {
+		if v == 'ư' {
+			return i
+		}
+	}
+	switch {
+	case len(vowels) == 1:
+		return 0
+	case len(vowels) > 3:
+		return -1
+	case hasFinal:
+		return len(vowels) - 1
+	case len(vowels) == 3:
+		return 1
+	}
+	switch string(vowels) {
+	case "oa", "oe", "uy":
+		if style == ToneStyleNew {
+			return 1
+		}
+	}
+	return 0
+}
+
+// ErrInvalidSyllable is returned when a word is not a Vietnamese syllable
+var ErrInvalidSyllable = errors.New("invalid Vietnamese syllable")
+
+// Syllable is a Vietnamese syllable (âm tiết) split to its components,
+// all components are lower case and toneless,
+// example: "quyển" => {Initial: "q", Medial: "u", Nucleus: "yê", Final: "n", Tone: ToneHook}
+type Syllable struct {
+	Initial string // âm đầu, can be empty
+	Medial  string // âm đệm: "o", "u" or empty
+	Nucleus string // âm chính, never empty
+	Final   string // âm cuối, can be a consonant or a semivowel "i", "y", "o", "u"
+	Tone    Tone
+}
+
+// vietnamInitials is ordered so that longer initials come first
+var vietnamInitials = []string{
+	"ngh", "ng", "nh", "gh", "gi", "kh", "ch", "ph", "th", "tr",
+	"b", "c", "d", "đ", "g", "h", "k", "l", "m", "n", "p", "q", "r", "s", "t", "v", "x",
+}
+
+// vietnamFinals is ordered so that longer finals come first
+var vietnamFinals = []string{"ng", "nh", "ch", "c", "m", "n", "p", "t"}
+
+// vietnamNuclei is ordered so that diphthongs come first
+var vietnamNuclei = []string{
+	"iê", "yê", "ia", "ya", "uô", "ua", "ươ", "ưa", "oo", "ôô",
+	"a", "ă", "â", "e", "ê", "i", "y", "o", "ô", "ơ", "u", "ư",
+}
+
+// vietnamSemivowelFinals maps a nucleus to its possible semivowel finals
+var vietnamSemivowelFinals = map[string]string{
+	"a": "iyou", "â": "yu", "e": "o", "ê": "u", "i": "u", "y": "u",
+	"o": "i", "ô": "i", "ơ": "iu", "u": "i", "ư": "iu",
+	"iê": "u", "yê": "u", "uô": "i", "ươ": "iu",
+}
+
+// ParseVietnameseSyllable splits a word to Vietnamese syllable components,
+// returns ErrInvalidSyllable if the word cannot be a Vietnamese syllable
+func ParseVietnameseSyllable(s string) (Syllable, error) {
+	var ret Syllable
+	word := make([]rune, 0, len(s))
+	for _, r := range []rune(strings.ToLower(s)) {
+		base, tone := splitVowelToTone(r)
+		if tone != ToneNone {
+			if ret.Tone != ToneNone {
+				return ret, fmt.Errorf("%w: %v: more than one tone mark", ErrInvalidSyllable, s)
+			}
+			ret.Tone = tone
+		}
+		word = append(word, base)
+	}
+	rest := string(word)
+
+	for _, initial := range vietnamInitials {
+		if strings.HasPrefix(rest, initial) {
+			ret.Initial, rest = initial, rest[len(initial):]
+			break
+		}
+	}
+	// "gi" is the initial only if it is followed by a vowel: "gia", "gì"
+	if ret.Initial == "gi" && (rest == "" || !isVietnamVowel([]rune(rest)[0])) {
+		ret.Initial, rest = "g", "i"+rest
+	}
+
+	vowelEnd := strings.IndexFunc(rest, func(r rune) bool { return !isVietnamVowel(r) })
+	if vowelEnd == -1 {
+		vowelEnd = len(rest)
+	}
+	vowels, final := rest[:vowelEnd], rest[vowelEnd:]
+	if final != "" {
+		found := false
+		for _, f := range vietnamFinals {
+			if final == f {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ret, fmt.Errorf("%w: %v: unexpected final %q", ErrInvalidSyllable, s, final)
+		}
+		ret.Final = final
+	}
+
+	switch {
+	case ret.Initial == "q":
+		if !strings.HasPrefix(vowels, "u") || len(vowels) == len("u") {
+			return ret, fmt.Errorf("%w: %v: \"q\" must be followed by \"u\"", ErrInvalidSyllable, s)
+		}
+		ret.Medial, vowels = "u", vowels[len("u"):]
+	case strings.HasPrefix(vowels, "oa"), strings.HasPrefix(vowels, "oă"),
+		strings.HasPrefix(vowels, "oe"):
+		ret.Medial, vowels = "o", vowels[len("o"):]
+	case strings.HasPrefix(vowels, "uâ"), strings.HasPrefix(vowels, "uê"),
+		strings.HasPrefix(vowels, "uy"), strings.HasPrefix(vowels, "uơ"):
+		ret.Medial, vowels = "u", vowels[len("u"):]
+	}
+
+	for _, nucleus := range vietnamNuclei {
+		if strings.HasPrefix(vowels, nucleus) {
+			ret.Nucleus, vowels = nucleus, vowels[len(nucleus):]
+			break
+		}
+	}
+	if ret.Nucleus == "" {
+		return ret, fmt.Errorf("%w: %v: no vowel", ErrInvalidSyllable, s)
+	}
+	if vowels != "" {
+		if ret.Final != "" || len([]rune(vowels)) > 1 ||
+			!strings.Contains(vietnamSemivowelFinals[ret.Nucleus], vowels) {
+			return ret, fmt.Errorf("%w: %v: unexpected vowel cluster", ErrInvalidSyllable, s)
+		}
+		ret.Final = vowels
+	}
+	if err := ret.validate(); err != nil {
+		return ret, fmt.Errorf("%w: %v: %v", ErrInvalidSyllable, s, err)
+	}
+	return ret, nil
+}
+
+// validate checks combination rules between the syllable components
+func (s Syllable) validate() error {
+	isStopFinal := s.Final == "c" || s.Final == "ch" || s.Final == "p" || s.Final == "t"
+	switch s.Nucleus {
+	case "ă", "â":
+		if s.Final == "" {
+			return fmt.Errorf("nucleus %q needs a final", s.Nucleus)
+		}
+	case "iê", "yê", "uô", "ươ", "oo", "ôô":
+		if s.Final == "" {
+			return fmt.Errorf("nucleus %q needs a final", s.Nucleus)
+		}
+	case "ia", "ya", "ua", "ưa":
+		if s.Final != "" {
+			return fmt.Errorf("nucleus %q cannot have a final", s.Nucleus)
+		}
+	}
+	switch s.Medial {
+	case "o":
+		if s.Nucleus != "a" && s.Nucleus != "ă" && s.Nucleus != "e" {
+			return fmt.Errorf("medial \"o\" before nucleus %q", s.Nucleus)
+		}
+	case "u":
+		if s.Initial != "q" {
+			switch s.Nucleus {
+			case "â", "ê", "y", "ơ", "yê", "ya":
+			default:
+				return fmt.Errorf("medial \"u\" before nucleus %q", s.Nucleus)
+			}
+		} else if strings.HasPrefix(s.Nucleus, "u") || strings.HasPrefix(s.Nucleus, "ư") ||
+			s.Nucleus == "o" {
+			return fmt.Errorf("\"qu\" before nucleus %q", s.Nucleus)
+		}
+	}
+	if s.Medial == "" {
+		switch s.Nucleus {
+		case "yê":
+			if s.Initial != "" {
+				return errors.New("nucleus \"yê\" after an initial consonant")
+			}
+		case "ya":
+			return errors.New("nucleus \"ya\" without medial")
+		}
+	}
+	frontVowel := s.Medial == "" && (strings.HasPrefix(s.Nucleus, "i") ||
+		strings.HasPrefix(s.Nucleus, "e") || strings.HasPrefix(s.Nucleus, "ê") ||
+		(s.Initial == "k" && strings.HasPrefix(s.Nucleus, "y")))
+	switch s.Initial {
+	case "k", "gh", "ngh":
+		if !frontVowel {
+			return fmt.Errorf("initial %q before nucleus %q", s.Initial, s.Nucleus)
+		}
+	case "c", "g", "ng":
+		// "gì", "gìn" are parsed as initial "g" and nucleus "i"
+		if frontVowel && !(s.Initial == "g" && s.Nucleus == "i") {
+			return fmt.Errorf("initial %q before nucleus %q", s.Initial, s.Nucleus)
+		}
+	}
+	switch s.Final {
+	case "ch", "nh":
+		switch s.Nucleus {
+		case "a", "ê", "i", "y":
+		default:
+			return fmt.Errorf("final %q after nucleus %q", s.Final, s.Nucleus)
+		}
+	}
+	if isStopFinal && s.Tone != ToneAcute && s.Tone != ToneDot {
+		return fmt.Errorf("final %q with tone %d", s.Final, s.Tone)
+	}
+	return nil
+}
+
+// String returns the syllable in lower case with the tone mark placed by
+// ToneStyleOld, example: "hòa", "quyển"
+func (s Syllable) String() string {
+	return s.Format(ToneStyleOld)
+}
+
+// Format returns the syllable in lower case with the tone mark placed by
+// the style
+func (s Syllable) Format(style ToneStyle) string {
+	initial, vowels, final := s.Initial, []rune(s.Medial+s.Nucleus), s.Final
+	if f := []rune(s.Final); len(f) == 1 && isVietnamVowel(f[0]) {
+		vowels, final = append(vowels, f[0]), ""
+	}
+	if initial == "q" && s.Medial == "u" {
+		initial, vowels = "qu", vowels[1:]
+	}
+	if pos := findTonePosition(vowels, final != "", style); pos != -1 {
+		vowels[pos] = applyTone(vowels[pos], s.Tone)
+	}
+	return initial + string(vowels) + final
+}
+
+// IsValidVietnameseSyllable returns true if the word can be a Vietnamese
+// syllable, example: "nghiêng" is valid, "ngiêng" or "office" are invalid
+func IsValidVietnameseSyllable(word string) bool {
+	_, err := ParseVietnameseSyllable(word)
+	return err == nil
+}
@@ -0,0 +1,113 @@
+package textproc
+
+import "testing"
+
+func TestNormalizeVietnameseTone(t *testing.T) {
+	for _, test := range []struct {
+		in    string
+		style ToneStyle
+		out   string
+	}{
+		{in: "hoà", style: ToneStyleOld, out: "hòa"},
+		{in: "hòa", style: ToneStyleNew, out: "hoà"},
+		{in: "khoẻ", style: ToneStyleOld, out: "khỏe"},
+		{in: "thủy", style: ToneStyleNew, out: "thuỷ"},
+		{in: "Uỷ ban", style: ToneStyleOld, out: "Ủy ban"},
+		{in: "HOÀ BÌNH", style: ToneStyleOld, out: "HÒA BÌNH"},
+		{in: "hoàng", style: ToneStyleOld, out: "hoàng"},
+		{in: "hòang", style: ToneStyleNew, out: "hoàng"},
+		{in: "quý", style: ToneStyleOld, out: "quý"},
+		{in: "qúy", style: ToneStyleOld, out: "quý"},
+		{in: "gìa", style: ToneStyleOld, out: "già"},
+		{in: "gì", style: ToneStyleNew, out: "gì"},
+		{in: "ngừơi", style: ToneStyleOld, out: "người"},
+		{in: "tòan", style: ToneStyleOld, out: "toàn"},
+		{in: "ngòai", style: ToneStyleNew, out: "ngoài"},
+		{in: "khủyu", style: ToneStyleOld, out: "khuỷu"},
+		{in: "của mía", style: ToneStyleNew, out: "của mía"},
+		{in: "tuyết", style: ToneStyleNew, out: "tuyết"},
+		{in: "Français café", style: ToneStyleNew, out: "Français café"},
+		{in: "Raúl país", style: ToneStyleOld, out: "Raúl país"},
+		{in: "Raúl país", style: ToneStyleNew, out: "Raúl país"},
+		{in: "hòa, hoà; hóa-hoá", style: ToneStyleOld, out: "hòa, hòa; hóa-hóa"},
+	} {
+		r, e := NormalizeVietnameseTone(test.in, test.style), test.out
+		if r != e {
+			t.Errorf("error NormalizeVietnameseTone %v: real: %v, expected: %v",
+				test.in, r, e)
+		}
+	}
+}
+
+func TestNormalizeTextTone(t *testing.T) {
+	words := TextToWords(NormalizeText("Hoà bình, hòa bình"))
+	if len(words) != 4 || words[0] != "Hòa" || words[2] != "hòa" {
+		t.Errorf("error NormalizeText tone: real: %v", words)
+	}
+	// not Vietnamese syllables, the tone mark is kept in place
+	if r, e := NormalizeText("Raúl, país"), "Raúl, país"; r != e {
+		t.Errorf("error NormalizeText foreign words: real: %v, expected: %v", r, e)
+	}
+}
+
+func TestParseVietnameseSyllable(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		out Syllable
+	}{
+		{in: "quyển", out: Syllable{Initial: "q", Medial: "u", Nucleus: "yê", Final: "n", Tone: ToneHook}},
+		{in: "Nghiêng", out: Syllable{Initial: "ngh", Nucleus: "iê", Final: "ng"}},
+		{in: "hoà", out: Syllable{Initial: "h", Medial: "o", Nucleus: "a", Tone: ToneGrave}},
+		{in: "người", out: Syllable{Initial: "ng", Nucleus: "ươ", Final: "i", Tone: ToneGrave}},
+		{in: "gì", out: Syllable{Initial: "g", Nucleus: "i", Tone: ToneGrave}},
+		{in: "giữa", out: Syllable{Initial: "gi", Nucleus: "ưa", Tone: ToneTilde}},
+		{in: "khuỷu", out: Syllable{Initial: "kh", Medial: "u", Nucleus: "y", Final: "u", Tone: ToneHook}},
+		{in: "yêu", out: Syllable{Nucleus: "yê", Final: "u"}},
+		{in: "ăn", out: Syllable{Nucleus: "ă", Final: "n"}},
+		{in: "đắt", out: Syllable{Initial: "đ", Nucleus: "ă", Final: "t", Tone: ToneAcute}},
+		{in: "kỹ", out: Syllable{Initial: "k", Nucleus: "y", Tone: ToneTilde}},
+	} {
+		r, err := ParseVietnameseSyllable(test.in)
+		if err != nil {
+			t.Errorf("error ParseVietnameseSyllable %v: %v", test.in, err)
+			continue
+		}
+		if r != test.out {
+			t.Errorf("error ParseVietnameseSyllable %v: real: %+v, expected: %+v",
+				test.in, r, test.out)
+		}
+	}
+
+	for _, invalid := range []string{
+		"", "office", "ngiêng", "ghà", "kà", "ă", "bàt", "hoà̀", "xyz", "chuyên1",
+		"quu", "tiê", "cia", "http", "2.0",
+	} {
+		if IsValidVietnameseSyllable(invalid) {
+			t.Errorf("error IsValidVietnameseSyllable %q: real: true, expected: false", invalid)
+		}
+	}
+	for _, valid := range []string{"Có", "thánh", "này", "chắc", "chắn", "Sẻ", "đệ",
+		"sẽ", "thêm", "sức", "mạnh", "để", "đả", "bại", "Sơ", "Luyến", "Trực",
+		"tiếp", "ngay", "bây", "giờ", "trên", "khuya", "quốc", "xoong", "oẳn"} {
+		if !IsValidVietnameseSyllable(valid) {
+			_, err := ParseVietnameseSyllable(valid)
+			t.Errorf("error IsValidVietnameseSyllable %q: %v", valid, err)
+		}
+	}
+}
+
+func TestSyllableFormat(t *testing.T) {
+	for _, word := range []string{"hòa", "quyển", "người", "khuỷu", "gì", "giữa", "thủy", "xoong"} {
+		s, err := ParseVietnameseSyllable(word)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r := s.String(); r != word {
+			t.Errorf("error Syllable String: real: %v, expected: %v", r, word)
+		}
+	}
+	s, _ := ParseVietnameseSyllable("khỏe")
+	if r := s.Format(ToneStyleNew); r != "khoẻ" {
+		t.Errorf("error Syllable Format: real: %v, expected: khoẻ", r)
+	}
+}
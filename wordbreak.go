@@ -0,0 +1,152 @@
+package textproc
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// WordSplitMode is the algorithm that TextToWordsWithOptions uses
+type WordSplitMode int
+
+// Word split modes
+const (
+	// WordSplitFast is TextToWords: words are separated by spaces,
+	// chars that are not in AlphaNumeric (digits, English and Vietnamese
+	// letters) are trimmed. It is the fastest mode for Vietnamese text.
+	WordSplitFast WordSplitMode = iota
+	// WordSplitUnicode follows Unicode word boundary rules (UAX #29), it
+	// works for all scripts that separate words by spaces (Cyrillic, Greek,
+	// ...). Chinese, Japanese and Thai are split into single chars.
+	WordSplitUnicode
+	// WordSplitDictionary is WordSplitUnicode, but Chinese, Japanese and
+	// Thai runs are split by forward longest matching with a lexicon
+	WordSplitDictionary
+)
+
+// WordsOptions configures TextToWordsWithOptions
+type WordsOptions struct {
+	Mode WordSplitMode
+	// Lexicon is used by WordSplitDictionary, default is DictionaryLexicon
+	Lexicon *Lexicon
+}
+
+// DictionaryLexicon is the default lexicon of WordSplitDictionary, it is a
+// small list of common words, callers should replace it with a full
+// dictionary or Add words to it
+var DictionaryLexicon = NewLexicon(strings.Fields(defaultDictionaryLexicon))
+
+// TextToWordsWithOptions splits a text to list of words (punctuations
+// removed) with the algorithm opts.Mode,
+// example with WordSplitDictionary: "中文（繁體）" => ["中文", "繁體"]
+func TextToWordsWithOptions(text string, opts WordsOptions) []string {
+	if opts.Mode == WordSplitFast {
+		return TextToWords(text)
+	}
+	lex := opts.Lexicon
+	if lex == nil {
+		lex = DictionaryLexicon
+	}
+	ret := make([]string, 0)
+	var run strings.Builder // consecutive segments of dictionary scripts
+	flushRun := func() {
+		if run.Len() > 0 {
+			ret = append(ret, segmentByDictionary([]rune(run.String()), lex)...)
+			run.Reset()
+		}
+	}
+	state := -1
+	for rest := text; len(rest) > 0; {
+		var segment string
+		segment, rest, state = uniseg.FirstWordInString(rest, state)
+		first, _ := utf8.DecodeRuneInString(segment)
+		if opts.Mode == WordSplitDictionary && dictionaryScript(first) != nil {
+			run.WriteString(segment)
+			continue
+		}
+		flushRun()
+		if strings.IndexFunc(segment, isLetterOrDigit) != -1 {
+			ret = append(ret, segment)
+		}
+	}
+	flushRun()
+	return ret
+}
+
+func isLetterOrDigit(char rune) bool {
+	return unicode.IsLetter(char) || unicode.IsDigit(char)
+}
+
+// dictionaryScript returns the script of chars that need a dictionary to
+// find word boundaries, returns nil for other chars
+func dictionaryScript(char rune) *unicode.RangeTable {
+	for _, script := range []*unicode.RangeTable{
+		unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Thai} {
+		if unicode.Is(script, char) {
+			return script
+		}
+	}
+	if char == 'ー' { // prolonged sound mark, script Common
+		return unicode.Katakana
+	}
+	return nil
+}
+
+// segmentByDictionary splits a run of CJK or Thai chars by forward longest
+// matching. Unknown Thai or Katakana chars are joined into one word until the
+// next known word, unknown Han and Hiragana chars are single char words.
+func segmentByDictionary(runes []rune, lex *Lexicon) []string {
+	// the run is normalized once, candidates are substrings of the key
+	key := lexiconKey(string(runes))
+	offsets := make([]int, 0, len(runes)+1) // byte offset of each rune in key
+	for i := range key {
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(key))
+	if len(offsets) != len(runes)+1 { // lowercase changed number of runes
+		runes = []rune(key)
+	}
+	ret := make([]string, 0)
+	unknownStart := -1
+	var unknownScript *unicode.RangeTable
+	flushUnknown := func(end int) {
+		if unknownStart != -1 {
+			ret = append(ret, string(runes[unknownStart:end]))
+			unknownStart = -1
+		}
+	}
+	for i := 0; i < len(runes); {
+		matched := 0
+		for l := min(lex.maxRunes, len(runes)-i); l >= 1; l-- {
+			if lex.words[key[offsets[i]:offsets[i+l]]] {
+				matched = l
+				break
+			}
+		}
+		if matched > 0 {
+			flushUnknown(i)
+			ret = append(ret, string(runes[i:i+matched]))
+			i += matched
+			continue
+		}
+		script := dictionaryScript(runes[i])
+		if unknownStart != -1 && script != unknownScript {
+			flushUnknown(i)
+		}
+		switch {
+		case script == unicode.Thai || script == unicode.Katakana:
+			if unknownStart == -1 {
+				unknownStart, unknownScript = i, script
+			}
+		default:
+			if isLetterOrDigit(runes[i]) {
+				ret = append(ret, string(runes[i]))
+			}
+		}
+		i++
+	}
+	flushUnknown(len(runes))
+	return ret
+}
@@ -0,0 +1,45 @@
+package textproc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTextToWordsWithOptions(t *testing.T) {
+	for i, c := range []struct {
+		text     string
+		opts     WordsOptions
+		expected []string
+	}{
+		{"Có thánh này, chắc chắn (NDB 2.0)", WordsOptions{},
+			[]string{"Có", "thánh", "này", "chắc", "chắn", "NDB", "2.0"}},
+		{"«Привет, мир!» Ελληνικά: can't 2.0", WordsOptions{Mode: WordSplitUnicode},
+			[]string{"Привет", "мир", "Ελληνικά", "can't", "2.0"}},
+		{"中文（繁體）", WordsOptions{Mode: WordSplitUnicode},
+			[]string{"中", "文", "繁", "體"}},
+		{"中文（繁體）", WordsOptions{Mode: WordSplitDictionary},
+			[]string{"中文", "繁體"}},
+		{"東京タワーへ行きます", WordsOptions{Mode: WordSplitDictionary},
+			[]string{"東京タワー", "へ", "行きます"}},
+		{"ภาษาไทยง่าย ครับ", WordsOptions{Mode: WordSplitDictionary},
+			[]string{"ภาษาไทย", "ง่าย", "ครับ"}},
+		{"สวัสดีชาวโลก", WordsOptions{Mode: WordSplitDictionary},
+			[]string{"สวัสดี", "ชาวโลก"}},
+		{"Tiếng Việt 中华人民共和国", WordsOptions{Mode: WordSplitDictionary},
+			[]string{"Tiếng", "Việt", "中华人民共和国"}},
+		{"猫は可愛い", WordsOptions{Mode: WordSplitDictionary,
+			Lexicon: NewLexicon([]string{"可愛い"})},
+			[]string{"猫", "は", "可愛い"}},
+		{"กขค", WordsOptions{Mode: WordSplitDictionary,
+			Lexicon: NewLexicon([]string{"ข"})},
+			[]string{"ก", "ข", "ค"}},
+		{"ทีวีและวิทยุ", WordsOptions{Mode: WordSplitDictionary,
+			Lexicon: NewLexicon([]string{"ทีวี", "และ", "วิทยุ"})},
+			[]string{"ทีวี", "และ", "วิทยุ"}},
+	} {
+		r := TextToWordsWithOptions(c.text, c.opts)
+		if !reflect.DeepEqual(r, c.expected) {
+			t.Errorf("error TextToWordsWithOptions %v: real: %q, expected: %q", i, r, c.expected)
+		}
+	}
+}
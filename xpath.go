@@ -0,0 +1,145 @@
+package textproc
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+
+	"github.com/antchfx/htmlquery"
+	"github.com/antchfx/xpath"
+	"golang.org/x/net/html"
+)
+
+// CompiledXPath is a compiled XPath expression that can be reused for many
+// documents, it is safe for concurrent use
+type CompiledXPath struct {
+	expr *xpath.Expr
+}
+
+// Compile compiles an XPath expression to a CompiledXPath
+func Compile(xPath string) (*CompiledXPath, error) {
+	expr, err := xpath.Compile(xPath)
+	if err != nil {
+		return nil, fmt.Errorf("error xpath Compile: %v", err)
+	}
+	return &CompiledXPath{expr: expr}, nil
+}
+
+// MustCompileXPath is like Compile but panics if the expression is
+// invalid, it is used to initialize global variables
+func MustCompileXPath(xPath string) *CompiledXPath {
+	ret, err := Compile(xPath)
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
+// String returns the source expression
+func (x *CompiledXPath) String() string {
+	return x.expr.String()
+}
+
+// First returns the first matched node, returns nil if no node matches
+func (x *CompiledXPath) First(node *html.Node) *html.Node {
+	return htmlquery.QuerySelector(node, x.expr)
+}
+
+// All returns all matched nodes
+func (x *CompiledXPath) All(node *html.Node) []*html.Node {
+	return htmlquery.QuerySelectorAll(node, x.expr)
+}
+
+// Count returns number of matched nodes
+func (x *CompiledXPath) Count(node *html.Node) int {
+	ret := 0
+	// Expr_Evaluate is not safe for concurrent use, Expr_Select is
+	iter := x.expr.Select(htmlquery.CreateXPathNavigator(node))
+	for iter.MoveNext() {
+		ret++
+	}
+	return ret
+}
+
+// Text returns text of the first matched node (the value if the node is an
+// attribute), same as HTMLGetText. Returns empty string if no node matches.
+func (x *CompiledXPath) Text(node *html.Node) string {
+	first := x.First(node)
+	if first == nil {
+		return ""
+	}
+	return HTMLGetText(first)
+}
+
+// Attr returns value of the attribute key of the first matched node,
+// returns empty string if no node matches or the node does not have the key
+func (x *CompiledXPath) Attr(node *html.Node, key string) string {
+	first := x.First(node)
+	if first == nil {
+		return ""
+	}
+	return htmlGetAttr(first, key)
+}
+
+// xpathCacheSize is max number of compiled expressions in the HTMLXPath cache
+const xpathCacheSize = 512
+
+var globalXPathCache = newXPathCache(xpathCacheSize)
+
+// xpathCache is a concurrency-safe LRU cache of compiled expressions
+type xpathCache struct {
+	mutex    sync.Mutex
+	capacity int
+	order    *list.List               // front is the most recently used
+	elements map[string]*list.Element // value of an element is *xpathCacheEntry
+}
+
+type xpathCacheEntry struct {
+	xPath    string
+	compiled *CompiledXPath
+}
+
+func newXPathCache(capacity int) *xpathCache {
+	return &xpathCache{
+		capacity: capacity,
+		order:    list.New(),
+		elements: make(map[string]*list.Element),
+	}
+}
+
+// get returns the compiled expression from the cache, the expression is
+// compiled and cached if it is not in the cache, invalid expressions are
+// not cached
+func (c *xpathCache) get(xPath string) (*CompiledXPath, error) {
+	c.mutex.Lock()
+	if e, found := c.elements[xPath]; found {
+		c.order.MoveToFront(e)
+		c.mutex.Unlock()
+		return e.Value.(*xpathCacheEntry).compiled, nil
+	}
+	c.mutex.Unlock()
+
+	compiled, err := Compile(xPath) // compile without holding the lock
+	if err != nil {
+		return nil, err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if e, found := c.elements[xPath]; found { // compiled by another goroutine
+		c.order.MoveToFront(e)
+		return e.Value.(*xpathCacheEntry).compiled, nil
+	}
+	c.elements[xPath] = c.order.PushFront(&xpathCacheEntry{xPath: xPath, compiled: compiled})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.elements, oldest.Value.(*xpathCacheEntry).xPath)
+	}
+	return compiled, nil
+}
+
+func (c *xpathCache) len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
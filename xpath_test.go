@@ -0,0 +1,78 @@
+package textproc
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCompiledXPath(t *testing.T) {
+	root := HTMLParseToNode(`<ul><li><a href="/1" title="một">Một</a></li>` +
+		`<li><a href="/2">Hai</a></li><li>Ba</li></ul>`)
+	links := MustCompileXPath("//li/a")
+	if r := links.Count(root); r != 2 {
+		t.Errorf("error CompiledXPath Count: real: %v, expected: %v", r, 2)
+	}
+	if r := len(links.All(root)); r != 2 {
+		t.Errorf("error CompiledXPath All: real: %v, expected: %v", r, 2)
+	}
+	if r := links.Text(root); r != "Một" {
+		t.Errorf("error CompiledXPath Text: real: %v, expected: %v", r, "Một")
+	}
+	if r := links.Attr(root, "title"); r != "một" {
+		t.Errorf("error CompiledXPath Attr: real: %v, expected: %v", r, "một")
+	}
+	if r := MustCompileXPath("//li[2]/a/@href").Text(root); r != "/2" {
+		t.Errorf("error CompiledXPath Text attribute: real: %v, expected: %v", r, "/2")
+	}
+	missing := MustCompileXPath("//table")
+	if missing.First(root) != nil || missing.Text(root) != "" || missing.Attr(root, "id") != "" {
+		t.Errorf("error CompiledXPath no match")
+	}
+	if links.String() != "//li/a" {
+		t.Errorf("error CompiledXPath String: %v", links.String())
+	}
+
+	if _, err := Compile("//a[@href"); err == nil {
+		t.Errorf("error Compile: expected an error")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("error MustCompileXPath: expected a panic")
+		}
+	}()
+	MustCompileXPath("//a[")
+}
+
+func TestXPathCache(t *testing.T) {
+	cache := newXPathCache(2)
+	a, _ := cache.get("//a")
+	cache.get("//b")
+	if a2, _ := cache.get("//a"); a2 != a {
+		t.Errorf("error xpathCache: expected a cached expression")
+	}
+	cache.get("//c") // evicts "//b", the least recently used
+	if _, found := cache.elements["//b"]; found || cache.len() != 2 {
+		t.Errorf("error xpathCache eviction: %v", cache.elements)
+	}
+	if _, err := cache.get("//["); err == nil || cache.len() != 2 {
+		t.Errorf("error xpathCache invalid expression: %v", err)
+	}
+
+	root := HTMLParseToNode(`<p>a</p><p>b</p>`)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				nodes, err := HTMLXPath(root, fmt.Sprintf("//p[%v] | //p", j%(i+1)+1))
+				if err != nil || len(nodes) != 2 {
+					t.Errorf("error concurrent HTMLXPath: %v, %v", len(nodes), err)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}